@@ -86,6 +86,12 @@ func WithRequestEditorFn(fn RequestEditorFn) ClientOption {
 
 // The interface specification for the client above.
 type ClientInterface interface {
+	// PendingDevices request
+	PendingDevices(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PendingFolders request
+	PendingFolders(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// Devices request
 	Devices(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
 
@@ -95,6 +101,18 @@ type ClientInterface interface {
 	// Completion request
 	Completion(ctx context.Context, params *CompletionParams, reqEditors ...RequestEditorFn) (*http.Response, error)
 
+	// LocalChanged request
+	LocalChanged(ctx context.Context, params *LocalChangedParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// FolderStatus request
+	FolderStatus(ctx context.Context, params *FolderStatusParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// Events request
+	Events(ctx context.Context, params *EventsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// DiskEvents request
+	DiskEvents(ctx context.Context, params *DiskEventsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// FolderErrors request
 	FolderErrors(ctx context.Context, params *FolderErrorsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
 
@@ -104,6 +122,9 @@ type ClientInterface interface {
 	// DeviceStats request
 	DeviceStats(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
 
+	// FolderStats request
+	FolderStats(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// Connections request
 	Connections(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
 
@@ -112,6 +133,36 @@ type ClientInterface interface {
 
 	// SystemStatus request
 	SystemStatus(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// SystemUpgrade request
+	SystemUpgrade(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// SystemVersion request
+	SystemVersion(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+}
+
+func (c *Client) PendingDevices(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPendingDevicesRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PendingFolders(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPendingFoldersRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
 }
 
 func (c *Client) Devices(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
@@ -150,6 +201,54 @@ func (c *Client) Completion(ctx context.Context, params *CompletionParams, reqEd
 	return c.Client.Do(req)
 }
 
+func (c *Client) LocalChanged(ctx context.Context, params *LocalChangedParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewLocalChangedRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) FolderStatus(ctx context.Context, params *FolderStatusParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewFolderStatusRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) Events(ctx context.Context, params *EventsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewEventsRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) DiskEvents(ctx context.Context, params *DiskEventsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDiskEventsRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
 func (c *Client) FolderErrors(ctx context.Context, params *FolderErrorsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
 	req, err := NewFolderErrorsRequest(c.Server, params)
 	if err != nil {
@@ -186,6 +285,18 @@ func (c *Client) DeviceStats(ctx context.Context, reqEditors ...RequestEditorFn)
 	return c.Client.Do(req)
 }
 
+func (c *Client) FolderStats(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewFolderStatsRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
 func (c *Client) Connections(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
 	req, err := NewConnectionsRequest(c.Server)
 	if err != nil {
@@ -222,6 +333,84 @@ func (c *Client) SystemStatus(ctx context.Context, reqEditors ...RequestEditorFn
 	return c.Client.Do(req)
 }
 
+func (c *Client) SystemUpgrade(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewSystemUpgradeRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) SystemVersion(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewSystemVersionRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+// NewPendingDevicesRequest generates requests for PendingDevices
+func NewPendingDevicesRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/rest/cluster/pending/devices")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewPendingFoldersRequest generates requests for PendingFolders
+func NewPendingFoldersRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/rest/cluster/pending/folders")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
 // NewDevicesRequest generates requests for Devices
 func NewDevicesRequest(server string) (*http.Request, error) {
 	var err error
@@ -313,8 +502,8 @@ func NewCompletionRequest(server string, params *CompletionParams) (*http.Reques
 	return req, nil
 }
 
-// NewFolderErrorsRequest generates requests for FolderErrors
-func NewFolderErrorsRequest(server string, params *FolderErrorsParams) (*http.Request, error) {
+// NewLocalChangedRequest generates requests for LocalChanged
+func NewLocalChangedRequest(server string, params *LocalChangedParams) (*http.Request, error) {
 	var err error
 
 	serverURL, err := url.Parse(server)
@@ -322,7 +511,7 @@ func NewFolderErrorsRequest(server string, params *FolderErrorsParams) (*http.Re
 		return nil, err
 	}
 
-	operationPath := fmt.Sprintf("/rest/folder/errors")
+	operationPath := fmt.Sprintf("/rest/db/localchanged")
 	if operationPath[0] == '/' {
 		operationPath = "." + operationPath
 	}
@@ -360,8 +549,8 @@ func NewFolderErrorsRequest(server string, params *FolderErrorsParams) (*http.Re
 	return req, nil
 }
 
-// NewHealthRequest generates requests for Health
-func NewHealthRequest(server string) (*http.Request, error) {
+// NewFolderStatusRequest generates requests for FolderStatus
+func NewFolderStatusRequest(server string, params *FolderStatusParams) (*http.Request, error) {
 	var err error
 
 	serverURL, err := url.Parse(server)
@@ -369,7 +558,7 @@ func NewHealthRequest(server string) (*http.Request, error) {
 		return nil, err
 	}
 
-	operationPath := fmt.Sprintf("/rest/noauth/health")
+	operationPath := fmt.Sprintf("/rest/db/status")
 	if operationPath[0] == '/' {
 		operationPath = "." + operationPath
 	}
@@ -379,6 +568,14 @@ func NewHealthRequest(server string) (*http.Request, error) {
 		return nil, err
 	}
 
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		queryValues.Add("folder", params.Folder)
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
 	req, err := http.NewRequest("GET", queryURL.String(), nil)
 	if err != nil {
 		return nil, err
@@ -387,8 +584,8 @@ func NewHealthRequest(server string) (*http.Request, error) {
 	return req, nil
 }
 
-// NewDeviceStatsRequest generates requests for DeviceStats
-func NewDeviceStatsRequest(server string) (*http.Request, error) {
+// NewEventsRequest generates requests for Events
+func NewEventsRequest(server string, params *EventsParams) (*http.Request, error) {
 	var err error
 
 	serverURL, err := url.Parse(server)
@@ -396,7 +593,7 @@ func NewDeviceStatsRequest(server string) (*http.Request, error) {
 		return nil, err
 	}
 
-	operationPath := fmt.Sprintf("/rest/stats/device")
+	operationPath := fmt.Sprintf("/rest/events")
 	if operationPath[0] == '/' {
 		operationPath = "." + operationPath
 	}
@@ -406,6 +603,36 @@ func NewDeviceStatsRequest(server string) (*http.Request, error) {
 		return nil, err
 	}
 
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Events != nil {
+
+			queryValues.Add("events", *params.Events)
+
+		}
+
+		if params.Since != nil {
+
+			queryValues.Add("since", *params.Since)
+
+		}
+
+		if params.Limit != nil {
+
+			queryValues.Add("limit", *params.Limit)
+
+		}
+
+		if params.Timeout != nil {
+
+			queryValues.Add("timeout", *params.Timeout)
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
 	req, err := http.NewRequest("GET", queryURL.String(), nil)
 	if err != nil {
 		return nil, err
@@ -414,8 +641,8 @@ func NewDeviceStatsRequest(server string) (*http.Request, error) {
 	return req, nil
 }
 
-// NewConnectionsRequest generates requests for Connections
-func NewConnectionsRequest(server string) (*http.Request, error) {
+// NewDiskEventsRequest generates requests for DiskEvents
+func NewDiskEventsRequest(server string, params *DiskEventsParams) (*http.Request, error) {
 	var err error
 
 	serverURL, err := url.Parse(server)
@@ -423,7 +650,7 @@ func NewConnectionsRequest(server string) (*http.Request, error) {
 		return nil, err
 	}
 
-	operationPath := fmt.Sprintf("/rest/system/connections")
+	operationPath := fmt.Sprintf("/rest/events/disk")
 	if operationPath[0] == '/' {
 		operationPath = "." + operationPath
 	}
@@ -433,6 +660,30 @@ func NewConnectionsRequest(server string) (*http.Request, error) {
 		return nil, err
 	}
 
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Since != nil {
+
+			queryValues.Add("since", *params.Since)
+
+		}
+
+		if params.Limit != nil {
+
+			queryValues.Add("limit", *params.Limit)
+
+		}
+
+		if params.Timeout != nil {
+
+			queryValues.Add("timeout", *params.Timeout)
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
 	req, err := http.NewRequest("GET", queryURL.String(), nil)
 	if err != nil {
 		return nil, err
@@ -441,8 +692,8 @@ func NewConnectionsRequest(server string) (*http.Request, error) {
 	return req, nil
 }
 
-// NewSystemErrorsRequest generates requests for SystemErrors
-func NewSystemErrorsRequest(server string) (*http.Request, error) {
+// NewFolderErrorsRequest generates requests for FolderErrors
+func NewFolderErrorsRequest(server string, params *FolderErrorsParams) (*http.Request, error) {
 	var err error
 
 	serverURL, err := url.Parse(server)
@@ -450,7 +701,7 @@ func NewSystemErrorsRequest(server string) (*http.Request, error) {
 		return nil, err
 	}
 
-	operationPath := fmt.Sprintf("/rest/system/error")
+	operationPath := fmt.Sprintf("/rest/folder/errors")
 	if operationPath[0] == '/' {
 		operationPath = "." + operationPath
 	}
@@ -460,6 +711,26 @@ func NewSystemErrorsRequest(server string) (*http.Request, error) {
 		return nil, err
 	}
 
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		queryValues.Add("folder", params.Folder)
+
+		if params.Page != nil {
+
+			queryValues.Add("page", *params.Page)
+
+		}
+
+		if params.Perpage != nil {
+
+			queryValues.Add("perpage", *params.Perpage)
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
 	req, err := http.NewRequest("GET", queryURL.String(), nil)
 	if err != nil {
 		return nil, err
@@ -468,8 +739,8 @@ func NewSystemErrorsRequest(server string) (*http.Request, error) {
 	return req, nil
 }
 
-// NewSystemStatusRequest generates requests for SystemStatus
-func NewSystemStatusRequest(server string) (*http.Request, error) {
+// NewHealthRequest generates requests for Health
+func NewHealthRequest(server string) (*http.Request, error) {
 	var err error
 
 	serverURL, err := url.Parse(server)
@@ -477,7 +748,7 @@ func NewSystemStatusRequest(server string) (*http.Request, error) {
 		return nil, err
 	}
 
-	operationPath := fmt.Sprintf("/rest/system/status")
+	operationPath := fmt.Sprintf("/rest/noauth/health")
 	if operationPath[0] == '/' {
 		operationPath = "." + operationPath
 	}
@@ -495,21 +766,210 @@ func NewSystemStatusRequest(server string) (*http.Request, error) {
 	return req, nil
 }
 
-func (c *Client) applyEditors(ctx context.Context, req *http.Request, additionalEditors []RequestEditorFn) error {
-	for _, r := range c.RequestEditors {
-		if err := r(ctx, req); err != nil {
-			return err
-		}
-	}
-	for _, r := range additionalEditors {
-		if err := r(ctx, req); err != nil {
-			return err
-		}
-	}
-	return nil
-}
+// NewDeviceStatsRequest generates requests for DeviceStats
+func NewDeviceStatsRequest(server string) (*http.Request, error) {
+	var err error
 
-// ClientWithResponses builds on ClientInterface to offer response payloads
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/rest/stats/device")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewFolderStatsRequest generates requests for FolderStats
+func NewFolderStatsRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/rest/stats/folder")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewConnectionsRequest generates requests for Connections
+func NewConnectionsRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/rest/system/connections")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewSystemErrorsRequest generates requests for SystemErrors
+func NewSystemErrorsRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/rest/system/error")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewSystemStatusRequest generates requests for SystemStatus
+func NewSystemStatusRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/rest/system/status")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewSystemUpgradeRequest generates requests for SystemUpgrade
+func NewSystemUpgradeRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/rest/system/upgrade")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewSystemVersionRequest generates requests for SystemVersion
+func NewSystemVersionRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/rest/system/version")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func (c *Client) applyEditors(ctx context.Context, req *http.Request, additionalEditors []RequestEditorFn) error {
+	for _, r := range c.RequestEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	for _, r := range additionalEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClientWithResponses builds on ClientInterface to offer response payloads
 type ClientWithResponses struct {
 	ClientInterface
 }
@@ -538,6 +998,12 @@ func WithBaseURL(baseURL string) ClientOption {
 
 // ClientWithResponsesInterface is the interface specification for the client with responses above.
 type ClientWithResponsesInterface interface {
+	// PendingDevicesWithResponse request
+	PendingDevicesWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*PendingDevicesResponse, error)
+
+	// PendingFoldersWithResponse request
+	PendingFoldersWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*PendingFoldersResponse, error)
+
 	// DevicesWithResponse request
 	DevicesWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*DevicesResponse, error)
 
@@ -547,6 +1013,18 @@ type ClientWithResponsesInterface interface {
 	// CompletionWithResponse request
 	CompletionWithResponse(ctx context.Context, params *CompletionParams, reqEditors ...RequestEditorFn) (*CompletionResponse, error)
 
+	// LocalChangedWithResponse request
+	LocalChangedWithResponse(ctx context.Context, params *LocalChangedParams, reqEditors ...RequestEditorFn) (*LocalChangedResponse, error)
+
+	// FolderStatusWithResponse request
+	FolderStatusWithResponse(ctx context.Context, params *FolderStatusParams, reqEditors ...RequestEditorFn) (*FolderStatusResponse, error)
+
+	// EventsWithResponse request
+	EventsWithResponse(ctx context.Context, params *EventsParams, reqEditors ...RequestEditorFn) (*EventsResponse, error)
+
+	// DiskEventsWithResponse request
+	DiskEventsWithResponse(ctx context.Context, params *DiskEventsParams, reqEditors ...RequestEditorFn) (*DiskEventsResponse, error)
+
 	// FolderErrorsWithResponse request
 	FolderErrorsWithResponse(ctx context.Context, params *FolderErrorsParams, reqEditors ...RequestEditorFn) (*FolderErrorsResponse, error)
 
@@ -556,6 +1034,9 @@ type ClientWithResponsesInterface interface {
 	// DeviceStatsWithResponse request
 	DeviceStatsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*DeviceStatsResponse, error)
 
+	// FolderStatsWithResponse request
+	FolderStatsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*FolderStatsResponse, error)
+
 	// ConnectionsWithResponse request
 	ConnectionsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ConnectionsResponse, error)
 
@@ -564,17 +1045,23 @@ type ClientWithResponsesInterface interface {
 
 	// SystemStatusWithResponse request
 	SystemStatusWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*SystemStatusResponse, error)
+
+	// SystemUpgradeWithResponse request
+	SystemUpgradeWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*SystemUpgradeResponse, error)
+
+	// SystemVersionWithResponse request
+	SystemVersionWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*SystemVersionResponse, error)
 }
 
-type DevicesResponse struct {
+type PendingDevicesResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
-	JSON200      *[]DeviceConfiguration
+	JSON200      *map[string]PendingDevice
 	JSONDefault  *Error
 }
 
 // Status returns HTTPResponse.Status
-func (r DevicesResponse) Status() string {
+func (r PendingDevicesResponse) Status() string {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.Status
 	}
@@ -582,22 +1069,22 @@ func (r DevicesResponse) Status() string {
 }
 
 // StatusCode returns HTTPResponse.StatusCode
-func (r DevicesResponse) StatusCode() int {
+func (r PendingDevicesResponse) StatusCode() int {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.StatusCode
 	}
 	return 0
 }
 
-type FoldersResponse struct {
+type PendingFoldersResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
-	JSON200      *[]FolderConfiguration
+	JSON200      *map[string]PendingFolder
 	JSONDefault  *Error
 }
 
 // Status returns HTTPResponse.Status
-func (r FoldersResponse) Status() string {
+func (r PendingFoldersResponse) Status() string {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.Status
 	}
@@ -605,22 +1092,22 @@ func (r FoldersResponse) Status() string {
 }
 
 // StatusCode returns HTTPResponse.StatusCode
-func (r FoldersResponse) StatusCode() int {
+func (r PendingFoldersResponse) StatusCode() int {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.StatusCode
 	}
 	return 0
 }
 
-type CompletionResponse struct {
+type DevicesResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
-	JSON200      *FolderCompletion
+	JSON200      *[]DeviceConfiguration
 	JSONDefault  *Error
 }
 
 // Status returns HTTPResponse.Status
-func (r CompletionResponse) Status() string {
+func (r DevicesResponse) Status() string {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.Status
 	}
@@ -628,22 +1115,22 @@ func (r CompletionResponse) Status() string {
 }
 
 // StatusCode returns HTTPResponse.StatusCode
-func (r CompletionResponse) StatusCode() int {
+func (r DevicesResponse) StatusCode() int {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.StatusCode
 	}
 	return 0
 }
 
-type FolderErrorsResponse struct {
+type FoldersResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
-	JSON200      *FolderErrors
+	JSON200      *[]FolderConfiguration
 	JSONDefault  *Error
 }
 
 // Status returns HTTPResponse.Status
-func (r FolderErrorsResponse) Status() string {
+func (r FoldersResponse) Status() string {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.Status
 	}
@@ -651,22 +1138,22 @@ func (r FolderErrorsResponse) Status() string {
 }
 
 // StatusCode returns HTTPResponse.StatusCode
-func (r FolderErrorsResponse) StatusCode() int {
+func (r FoldersResponse) StatusCode() int {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.StatusCode
 	}
 	return 0
 }
 
-type HealthResponse struct {
+type CompletionResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
-	JSON200      *HealthStatus
+	JSON200      *FolderCompletion
 	JSONDefault  *Error
 }
 
 // Status returns HTTPResponse.Status
-func (r HealthResponse) Status() string {
+func (r CompletionResponse) Status() string {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.Status
 	}
@@ -674,22 +1161,22 @@ func (r HealthResponse) Status() string {
 }
 
 // StatusCode returns HTTPResponse.StatusCode
-func (r HealthResponse) StatusCode() int {
+func (r CompletionResponse) StatusCode() int {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.StatusCode
 	}
 	return 0
 }
 
-type DeviceStatsResponse struct {
+type LocalChangedResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
-	JSON200      *map[string]DeviceStatistics
+	JSON200      *LocalChanged
 	JSONDefault  *Error
 }
 
 // Status returns HTTPResponse.Status
-func (r DeviceStatsResponse) Status() string {
+func (r LocalChangedResponse) Status() string {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.Status
 	}
@@ -697,22 +1184,22 @@ func (r DeviceStatsResponse) Status() string {
 }
 
 // StatusCode returns HTTPResponse.StatusCode
-func (r DeviceStatsResponse) StatusCode() int {
+func (r LocalChangedResponse) StatusCode() int {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.StatusCode
 	}
 	return 0
 }
 
-type ConnectionsResponse struct {
+type FolderStatusResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
-	JSON200      *Connections
+	JSON200      *FolderStatus
 	JSONDefault  *Error
 }
 
 // Status returns HTTPResponse.Status
-func (r ConnectionsResponse) Status() string {
+func (r FolderStatusResponse) Status() string {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.Status
 	}
@@ -720,22 +1207,22 @@ func (r ConnectionsResponse) Status() string {
 }
 
 // StatusCode returns HTTPResponse.StatusCode
-func (r ConnectionsResponse) StatusCode() int {
+func (r FolderStatusResponse) StatusCode() int {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.StatusCode
 	}
 	return 0
 }
 
-type SystemErrorsResponse struct {
+type EventsResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
-	JSON200      *SystemErrors
+	JSON200      *[]Event
 	JSONDefault  *Error
 }
 
 // Status returns HTTPResponse.Status
-func (r SystemErrorsResponse) Status() string {
+func (r EventsResponse) Status() string {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.Status
 	}
@@ -743,133 +1230,619 @@ func (r SystemErrorsResponse) Status() string {
 }
 
 // StatusCode returns HTTPResponse.StatusCode
-func (r SystemErrorsResponse) StatusCode() int {
+func (r EventsResponse) StatusCode() int {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.StatusCode
 	}
 	return 0
 }
 
-type SystemStatusResponse struct {
+type DiskEventsResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
-	JSON200      *SystemStatus
+	JSON200      *[]Event
 	JSONDefault  *Error
 }
 
 // Status returns HTTPResponse.Status
-func (r SystemStatusResponse) Status() string {
+func (r DiskEventsResponse) Status() string {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.Status
 	}
 	return http.StatusText(0)
 }
 
-// StatusCode returns HTTPResponse.StatusCode
-func (r SystemStatusResponse) StatusCode() int {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.StatusCode
+// StatusCode returns HTTPResponse.StatusCode
+func (r DiskEventsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type FolderErrorsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *FolderErrors
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r FolderErrorsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r FolderErrorsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type HealthResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *HealthStatus
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r HealthResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r HealthResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type DeviceStatsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *map[string]DeviceStatistics
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r DeviceStatsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r DeviceStatsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type FolderStatsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *map[string]FolderStatistics
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r FolderStatsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r FolderStatsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ConnectionsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Connections
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r ConnectionsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ConnectionsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type SystemErrorsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *SystemErrors
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r SystemErrorsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r SystemErrorsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type SystemStatusResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *SystemStatus
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r SystemStatusResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r SystemStatusResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type SystemUpgradeResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *SystemUpgrade
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r SystemUpgradeResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r SystemUpgradeResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type SystemVersionResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *SystemVersion
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r SystemVersionResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r SystemVersionResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+// PendingDevicesWithResponse request returning *PendingDevicesResponse
+func (c *ClientWithResponses) PendingDevicesWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*PendingDevicesResponse, error) {
+	rsp, err := c.PendingDevices(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePendingDevicesResponse(rsp)
+}
+
+// PendingFoldersWithResponse request returning *PendingFoldersResponse
+func (c *ClientWithResponses) PendingFoldersWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*PendingFoldersResponse, error) {
+	rsp, err := c.PendingFolders(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePendingFoldersResponse(rsp)
+}
+
+// DevicesWithResponse request returning *DevicesResponse
+func (c *ClientWithResponses) DevicesWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*DevicesResponse, error) {
+	rsp, err := c.Devices(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDevicesResponse(rsp)
+}
+
+// FoldersWithResponse request returning *FoldersResponse
+func (c *ClientWithResponses) FoldersWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*FoldersResponse, error) {
+	rsp, err := c.Folders(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseFoldersResponse(rsp)
+}
+
+// CompletionWithResponse request returning *CompletionResponse
+func (c *ClientWithResponses) CompletionWithResponse(ctx context.Context, params *CompletionParams, reqEditors ...RequestEditorFn) (*CompletionResponse, error) {
+	rsp, err := c.Completion(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCompletionResponse(rsp)
+}
+
+// LocalChangedWithResponse request returning *LocalChangedResponse
+func (c *ClientWithResponses) LocalChangedWithResponse(ctx context.Context, params *LocalChangedParams, reqEditors ...RequestEditorFn) (*LocalChangedResponse, error) {
+	rsp, err := c.LocalChanged(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseLocalChangedResponse(rsp)
+}
+
+// FolderStatusWithResponse request returning *FolderStatusResponse
+func (c *ClientWithResponses) FolderStatusWithResponse(ctx context.Context, params *FolderStatusParams, reqEditors ...RequestEditorFn) (*FolderStatusResponse, error) {
+	rsp, err := c.FolderStatus(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseFolderStatusResponse(rsp)
+}
+
+// EventsWithResponse request returning *EventsResponse
+func (c *ClientWithResponses) EventsWithResponse(ctx context.Context, params *EventsParams, reqEditors ...RequestEditorFn) (*EventsResponse, error) {
+	rsp, err := c.Events(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseEventsResponse(rsp)
+}
+
+// DiskEventsWithResponse request returning *DiskEventsResponse
+func (c *ClientWithResponses) DiskEventsWithResponse(ctx context.Context, params *DiskEventsParams, reqEditors ...RequestEditorFn) (*DiskEventsResponse, error) {
+	rsp, err := c.DiskEvents(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDiskEventsResponse(rsp)
+}
+
+// FolderErrorsWithResponse request returning *FolderErrorsResponse
+func (c *ClientWithResponses) FolderErrorsWithResponse(ctx context.Context, params *FolderErrorsParams, reqEditors ...RequestEditorFn) (*FolderErrorsResponse, error) {
+	rsp, err := c.FolderErrors(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseFolderErrorsResponse(rsp)
+}
+
+// HealthWithResponse request returning *HealthResponse
+func (c *ClientWithResponses) HealthWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*HealthResponse, error) {
+	rsp, err := c.Health(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseHealthResponse(rsp)
+}
+
+// DeviceStatsWithResponse request returning *DeviceStatsResponse
+func (c *ClientWithResponses) DeviceStatsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*DeviceStatsResponse, error) {
+	rsp, err := c.DeviceStats(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDeviceStatsResponse(rsp)
+}
+
+// FolderStatsWithResponse request returning *FolderStatsResponse
+func (c *ClientWithResponses) FolderStatsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*FolderStatsResponse, error) {
+	rsp, err := c.FolderStats(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseFolderStatsResponse(rsp)
+}
+
+// ConnectionsWithResponse request returning *ConnectionsResponse
+func (c *ClientWithResponses) ConnectionsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ConnectionsResponse, error) {
+	rsp, err := c.Connections(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseConnectionsResponse(rsp)
+}
+
+// SystemErrorsWithResponse request returning *SystemErrorsResponse
+func (c *ClientWithResponses) SystemErrorsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*SystemErrorsResponse, error) {
+	rsp, err := c.SystemErrors(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSystemErrorsResponse(rsp)
+}
+
+// SystemStatusWithResponse request returning *SystemStatusResponse
+func (c *ClientWithResponses) SystemStatusWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*SystemStatusResponse, error) {
+	rsp, err := c.SystemStatus(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSystemStatusResponse(rsp)
+}
+
+// SystemUpgradeWithResponse request returning *SystemUpgradeResponse
+func (c *ClientWithResponses) SystemUpgradeWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*SystemUpgradeResponse, error) {
+	rsp, err := c.SystemUpgrade(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSystemUpgradeResponse(rsp)
+}
+
+// SystemVersionWithResponse request returning *SystemVersionResponse
+func (c *ClientWithResponses) SystemVersionWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*SystemVersionResponse, error) {
+	rsp, err := c.SystemVersion(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSystemVersionResponse(rsp)
+}
+
+// ParsePendingDevicesResponse parses an HTTP response from a PendingDevicesWithResponse call
+func ParsePendingDevicesResponse(rsp *http.Response) (*PendingDevicesResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PendingDevicesResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest map[string]PendingDevice
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParsePendingFoldersResponse parses an HTTP response from a PendingFoldersWithResponse call
+func ParsePendingFoldersResponse(rsp *http.Response) (*PendingFoldersResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PendingFoldersResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest map[string]PendingFolder
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
 	}
-	return 0
+
+	return response, nil
 }
 
-// DevicesWithResponse request returning *DevicesResponse
-func (c *ClientWithResponses) DevicesWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*DevicesResponse, error) {
-	rsp, err := c.Devices(ctx, reqEditors...)
+// ParseDevicesResponse parses an HTTP response from a DevicesWithResponse call
+func ParseDevicesResponse(rsp *http.Response) (*DevicesResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
-	return ParseDevicesResponse(rsp)
-}
 
-// FoldersWithResponse request returning *FoldersResponse
-func (c *ClientWithResponses) FoldersWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*FoldersResponse, error) {
-	rsp, err := c.Folders(ctx, reqEditors...)
-	if err != nil {
-		return nil, err
+	response := &DevicesResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
 	}
-	return ParseFoldersResponse(rsp)
-}
 
-// CompletionWithResponse request returning *CompletionResponse
-func (c *ClientWithResponses) CompletionWithResponse(ctx context.Context, params *CompletionParams, reqEditors ...RequestEditorFn) (*CompletionResponse, error) {
-	rsp, err := c.Completion(ctx, params, reqEditors...)
-	if err != nil {
-		return nil, err
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest []DeviceConfiguration
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
 	}
-	return ParseCompletionResponse(rsp)
+
+	return response, nil
 }
 
-// FolderErrorsWithResponse request returning *FolderErrorsResponse
-func (c *ClientWithResponses) FolderErrorsWithResponse(ctx context.Context, params *FolderErrorsParams, reqEditors ...RequestEditorFn) (*FolderErrorsResponse, error) {
-	rsp, err := c.FolderErrors(ctx, params, reqEditors...)
+// ParseFoldersResponse parses an HTTP response from a FoldersWithResponse call
+func ParseFoldersResponse(rsp *http.Response) (*FoldersResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
-	return ParseFolderErrorsResponse(rsp)
-}
 
-// HealthWithResponse request returning *HealthResponse
-func (c *ClientWithResponses) HealthWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*HealthResponse, error) {
-	rsp, err := c.Health(ctx, reqEditors...)
-	if err != nil {
-		return nil, err
+	response := &FoldersResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
 	}
-	return ParseHealthResponse(rsp)
-}
 
-// DeviceStatsWithResponse request returning *DeviceStatsResponse
-func (c *ClientWithResponses) DeviceStatsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*DeviceStatsResponse, error) {
-	rsp, err := c.DeviceStats(ctx, reqEditors...)
-	if err != nil {
-		return nil, err
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest []FolderConfiguration
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
 	}
-	return ParseDeviceStatsResponse(rsp)
+
+	return response, nil
 }
 
-// ConnectionsWithResponse request returning *ConnectionsResponse
-func (c *ClientWithResponses) ConnectionsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ConnectionsResponse, error) {
-	rsp, err := c.Connections(ctx, reqEditors...)
+// ParseCompletionResponse parses an HTTP response from a CompletionWithResponse call
+func ParseCompletionResponse(rsp *http.Response) (*CompletionResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
-	return ParseConnectionsResponse(rsp)
-}
 
-// SystemErrorsWithResponse request returning *SystemErrorsResponse
-func (c *ClientWithResponses) SystemErrorsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*SystemErrorsResponse, error) {
-	rsp, err := c.SystemErrors(ctx, reqEditors...)
-	if err != nil {
-		return nil, err
+	response := &CompletionResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
 	}
-	return ParseSystemErrorsResponse(rsp)
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest FolderCompletion
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
 }
 
-// SystemStatusWithResponse request returning *SystemStatusResponse
-func (c *ClientWithResponses) SystemStatusWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*SystemStatusResponse, error) {
-	rsp, err := c.SystemStatus(ctx, reqEditors...)
+// ParseLocalChangedResponse parses an HTTP response from a LocalChangedWithResponse call
+func ParseLocalChangedResponse(rsp *http.Response) (*LocalChangedResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
-	return ParseSystemStatusResponse(rsp)
+
+	response := &LocalChangedResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest LocalChanged
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
 }
 
-// ParseDevicesResponse parses an HTTP response from a DevicesWithResponse call
-func ParseDevicesResponse(rsp *http.Response) (*DevicesResponse, error) {
+// ParseFolderStatusResponse parses an HTTP response from a FolderStatusWithResponse call
+func ParseFolderStatusResponse(rsp *http.Response) (*FolderStatusResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &DevicesResponse{
+	response := &FolderStatusResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
-		var dest []DeviceConfiguration
+		var dest FolderStatus
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
 			return nil, err
 		}
@@ -887,22 +1860,22 @@ func ParseDevicesResponse(rsp *http.Response) (*DevicesResponse, error) {
 	return response, nil
 }
 
-// ParseFoldersResponse parses an HTTP response from a FoldersWithResponse call
-func ParseFoldersResponse(rsp *http.Response) (*FoldersResponse, error) {
+// ParseEventsResponse parses an HTTP response from a EventsWithResponse call
+func ParseEventsResponse(rsp *http.Response) (*EventsResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &FoldersResponse{
+	response := &EventsResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
-		var dest []FolderConfiguration
+		var dest []Event
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
 			return nil, err
 		}
@@ -920,22 +1893,22 @@ func ParseFoldersResponse(rsp *http.Response) (*FoldersResponse, error) {
 	return response, nil
 }
 
-// ParseCompletionResponse parses an HTTP response from a CompletionWithResponse call
-func ParseCompletionResponse(rsp *http.Response) (*CompletionResponse, error) {
+// ParseDiskEventsResponse parses an HTTP response from a DiskEventsWithResponse call
+func ParseDiskEventsResponse(rsp *http.Response) (*DiskEventsResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &CompletionResponse{
+	response := &DiskEventsResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
-		var dest FolderCompletion
+		var dest []Event
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
 			return nil, err
 		}
@@ -1052,6 +2025,39 @@ func ParseDeviceStatsResponse(rsp *http.Response) (*DeviceStatsResponse, error)
 	return response, nil
 }
 
+// ParseFolderStatsResponse parses an HTTP response from a FolderStatsWithResponse call
+func ParseFolderStatsResponse(rsp *http.Response) (*FolderStatsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &FolderStatsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest map[string]FolderStatistics
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
 // ParseConnectionsResponse parses an HTTP response from a ConnectionsWithResponse call
 func ParseConnectionsResponse(rsp *http.Response) (*ConnectionsResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
@@ -1150,3 +2156,69 @@ func ParseSystemStatusResponse(rsp *http.Response) (*SystemStatusResponse, error
 
 	return response, nil
 }
+
+// ParseSystemUpgradeResponse parses an HTTP response from a SystemUpgradeWithResponse call
+func ParseSystemUpgradeResponse(rsp *http.Response) (*SystemUpgradeResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SystemUpgradeResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest SystemUpgrade
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseSystemVersionResponse parses an HTTP response from a SystemVersionWithResponse call
+func ParseSystemVersionResponse(rsp *http.Response) (*SystemVersionResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SystemVersionResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest SystemVersion
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
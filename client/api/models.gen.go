@@ -4,6 +4,7 @@
 package api
 
 import (
+	"encoding/json"
 	"time"
 )
 
@@ -77,6 +78,22 @@ type DeviceConfiguration struct {
 	Untrusted                bool             `json:"untrusted"`
 }
 
+// DeviceConnectedEventData defines model for DeviceConnectedEventData.
+type DeviceConnectedEventData struct {
+	Addr          string  `json:"addr"`
+	ClientName    *string `json:"clientName,omitempty"`
+	ClientVersion *string `json:"clientVersion,omitempty"`
+	DeviceName    string  `json:"deviceName"`
+	Id            string  `json:"id"`
+	Type          string  `json:"type"`
+}
+
+// DeviceDisconnectedEventData defines model for DeviceDisconnectedEventData.
+type DeviceDisconnectedEventData struct {
+	Error string `json:"error"`
+	Id    string `json:"id"`
+}
+
 // DeviceStatistics defines model for DeviceStatistics.
 type DeviceStatistics struct {
 	LastConnectionDurationS float64   `json:"lastConnectionDurationS"`
@@ -88,17 +105,48 @@ type DiscoveryStatusEntry struct {
 	Error string `json:"error"`
 }
 
+// DiskEventData defines model for DiskEventData.
+type DiskEventData struct {
+	Action     string  `json:"action"`
+	Folder     string  `json:"folder"`
+	FolderID   string  `json:"folderID"`
+	Label      string  `json:"label"`
+	ModifiedBy *string `json:"modifiedBy,omitempty"`
+	Path       string  `json:"path"`
+	Type       string  `json:"type"`
+}
+
 // Error defines model for Error.
 type Error struct {
 	Error string `json:"error"`
 }
 
+// Event defines model for Event.
+type Event struct {
+	Data     json.RawMessage `json:"data"`
+	GlobalID int             `json:"globalID"`
+	Id       int             `json:"id"`
+	Time     time.Time       `json:"time"`
+	Type     string          `json:"type"`
+}
+
 // FileError defines model for FileError.
 type FileError struct {
 	Error string `json:"error"`
 	Path  string `json:"path"`
 }
 
+// FileInfo defines model for FileInfo.
+type FileInfo struct {
+	Deleted    bool      `json:"deleted"`
+	Modified   time.Time `json:"modified"`
+	ModifiedBy *string   `json:"modifiedBy,omitempty"`
+	Name       string    `json:"name"`
+	Sequence   *int64    `json:"sequence,omitempty"`
+	Size       int64     `json:"size"`
+	Type       string    `json:"type"`
+}
+
 // FolderCompletion defines model for FolderCompletion.
 type FolderCompletion struct {
 	Completion  float64 `json:"completion"`
@@ -169,11 +217,76 @@ type FolderErrors struct {
 	Perpage int         `json:"perpage"`
 }
 
+// FolderErrorsEventData defines model for FolderErrorsEventData.
+type FolderErrorsEventData struct {
+	Errors []FileError `json:"errors"`
+	Folder string      `json:"folder"`
+}
+
+// FolderPausedEventData defines model for FolderPausedEventData.
+type FolderPausedEventData struct {
+	Id    string `json:"id"`
+	Label string `json:"label"`
+}
+
+// FolderStatistics defines model for FolderStatistics.
+type FolderStatistics struct {
+	LastFile LastFile  `json:"lastFile"`
+	LastScan time.Time `json:"lastScan"`
+}
+
+// FolderStatus defines model for FolderStatus.
+type FolderStatus struct {
+	Error                         string    `json:"error"`
+	Errors                        int       `json:"errors"`
+	GlobalBytes                   int64     `json:"globalBytes"`
+	GlobalDeleted                 int       `json:"globalDeleted"`
+	GlobalDirectories             int       `json:"globalDirectories"`
+	GlobalFiles                   int       `json:"globalFiles"`
+	GlobalSymlinks                int       `json:"globalSymlinks"`
+	GlobalTotalItems              int       `json:"globalTotalItems"`
+	IgnorePatterns                bool      `json:"ignorePatterns"`
+	InSyncBytes                   int64     `json:"inSyncBytes"`
+	InSyncFiles                   int       `json:"inSyncFiles"`
+	Invalid                       *string   `json:"invalid,omitempty"`
+	LocalBytes                    int64     `json:"localBytes"`
+	LocalDeleted                  int       `json:"localDeleted"`
+	LocalDirectories              int       `json:"localDirectories"`
+	LocalFiles                    int       `json:"localFiles"`
+	LocalSymlinks                 int       `json:"localSymlinks"`
+	LocalTotalItems               int       `json:"localTotalItems"`
+	NeedBytes                     int64     `json:"needBytes"`
+	NeedDeletes                   int       `json:"needDeletes"`
+	NeedDirectories               int       `json:"needDirectories"`
+	NeedFiles                     int       `json:"needFiles"`
+	NeedSymlinks                  int       `json:"needSymlinks"`
+	NeedTotalItems                int       `json:"needTotalItems"`
+	PullErrors                    int       `json:"pullErrors"`
+	ReceiveOnlyChangedBytes       int64     `json:"receiveOnlyChangedBytes"`
+	ReceiveOnlyChangedDeletes     int       `json:"receiveOnlyChangedDeletes"`
+	ReceiveOnlyChangedDirectories int       `json:"receiveOnlyChangedDirectories"`
+	ReceiveOnlyChangedFiles       int       `json:"receiveOnlyChangedFiles"`
+	ReceiveOnlyChangedSymlinks    int       `json:"receiveOnlyChangedSymlinks"`
+	ReceiveOnlyTotalItems         int       `json:"receiveOnlyTotalItems"`
+	Sequence                      int64     `json:"sequence"`
+	State                         string    `json:"state"`
+	StateChanged                  time.Time `json:"stateChanged"`
+	Version                       int64     `json:"version"`
+	WatchError                    string    `json:"watchError"`
+}
+
 // HealthStatus defines model for HealthStatus.
 type HealthStatus struct {
 	Status string `json:"status"`
 }
 
+// LastFile defines model for LastFile.
+type LastFile struct {
+	At       time.Time `json:"at"`
+	Deleted  bool      `json:"deleted"`
+	Filename string    `json:"filename"`
+}
+
 // ListenerStatusEntry defines model for ListenerStatusEntry.
 type ListenerStatusEntry struct {
 	Error        string   `json:"error"`
@@ -181,6 +294,13 @@ type ListenerStatusEntry struct {
 	WanAddresses []string `json:"wanAddresses"`
 }
 
+// LocalChanged defines model for LocalChanged.
+type LocalChanged struct {
+	Files   []FileInfo `json:"files"`
+	Page    int        `json:"page"`
+	Perpage int        `json:"perpage"`
+}
+
 // LogLine defines model for LogLine.
 type LogLine struct {
 	Level   int       `json:"level"`
@@ -195,12 +315,41 @@ type ObservedFolder struct {
 	Time  time.Time `json:"time"`
 }
 
+// PendingDevice defines model for PendingDevice.
+type PendingDevice struct {
+	Address string    `json:"address"`
+	Name    string    `json:"name"`
+	Time    time.Time `json:"time"`
+}
+
+// PendingFolder defines model for PendingFolder.
+type PendingFolder struct {
+	OfferedBy map[string]PendingFolderOffer `json:"offeredBy"`
+}
+
+// PendingFolderOffer defines model for PendingFolderOffer.
+type PendingFolderOffer struct {
+	Label            string    `json:"label"`
+	ReceiveEncrypted bool      `json:"receiveEncrypted"`
+	RemoteEncrypted  bool      `json:"remoteEncrypted"`
+	Time             time.Time `json:"time"`
+}
+
 // Size defines model for Size.
 type Size struct {
 	Unit  string  `json:"unit"`
 	Value float32 `json:"value"`
 }
 
+// StateChangedEventData defines model for StateChangedEventData.
+type StateChangedEventData struct {
+	Duration *float64 `json:"duration,omitempty"`
+	Error    *string  `json:"error,omitempty"`
+	Folder   string   `json:"folder"`
+	From     string   `json:"from"`
+	To       string   `json:"to"`
+}
+
 // SystemErrors defines model for SystemErrors.
 type SystemErrors struct {
 	Errors []LogLine `json:"errors"`
@@ -225,6 +374,30 @@ type SystemStatus struct {
 	UrVersionMax            int                              `json:"urVersionMax"`
 }
 
+// SystemUpgrade defines model for SystemUpgrade.
+type SystemUpgrade struct {
+	Latest     string `json:"latest"`
+	MajorNewer bool   `json:"majorNewer"`
+	Newer      bool   `json:"newer"`
+	Running    string `json:"running"`
+}
+
+// SystemVersion defines model for SystemVersion.
+type SystemVersion struct {
+	Arch        string     `json:"arch"`
+	Codename    string     `json:"codename"`
+	Date        *time.Time `json:"date,omitempty"`
+	IsBeta      bool       `json:"isBeta"`
+	IsCandidate bool       `json:"isCandidate"`
+	IsRelease   bool       `json:"isRelease"`
+	LongVersion string     `json:"longVersion"`
+	Os          string     `json:"os"`
+	Stamp       *string    `json:"stamp,omitempty"`
+	Tags        *[]string  `json:"tags,omitempty"`
+	User        *string    `json:"user,omitempty"`
+	Version     string     `json:"version"`
+}
+
 // VersioningConfiguration defines model for VersioningConfiguration.
 type VersioningConfiguration struct {
 	CleanupIntervalS int               `json:"cleanupIntervalS"`
@@ -258,6 +431,45 @@ type CompletionParams struct {
 	Device string `form:"device" json:"device"`
 }
 
+// LocalChangedParams defines parameters for LocalChanged.
+type LocalChangedParams struct {
+	Folder  string  `form:"folder" json:"folder"`
+	Page    *string `form:"page,omitempty" json:"page,omitempty"`
+	Perpage *string `form:"perpage,omitempty" json:"perpage,omitempty"`
+}
+
+// FolderStatusParams defines parameters for FolderStatus.
+type FolderStatusParams struct {
+	Folder string `form:"folder" json:"folder"`
+}
+
+// EventsParams defines parameters for Events.
+type EventsParams struct {
+	// Events comma separated list of event types
+	Events *string `form:"events,omitempty" json:"events,omitempty"`
+
+	// Since returns events with ID greater than since
+	Since *string `form:"since,omitempty" json:"since,omitempty"`
+
+	// Limit returns only last limit events
+	Limit *string `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Timeout how long to wait for new events, in seconds
+	Timeout *string `form:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+// DiskEventsParams defines parameters for DiskEvents.
+type DiskEventsParams struct {
+	// Since returns events with ID greater than since
+	Since *string `form:"since,omitempty" json:"since,omitempty"`
+
+	// Limit returns only last limit events
+	Limit *string `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Timeout how long to wait for new events, in seconds
+	Timeout *string `form:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
 // FolderErrorsParams defines parameters for FolderErrors.
 type FolderErrorsParams struct {
 	Folder  string  `form:"folder" json:"folder"`
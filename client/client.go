@@ -4,10 +4,15 @@ package client
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"iter"
 	"net/http"
+	"net/url"
 	"slices"
-	"strings"
+	"strconv"
+	"sync/atomic"
+	"time"
 
 	"github.com/dsh2dsh/check_syncthing/client/api"
 )
@@ -15,7 +20,10 @@ import (
 const authHeader = "X-API-Key"
 
 func New(baseURL string, opts ...Option) (*Client, error) {
-	c := &Client{baseURL: baseURL}
+	c := &Client{baseURL: baseURL, timeout: httpTimeout}
+	if err := c.withUnixSocket(); err != nil {
+		return nil, err
+	}
 	return c, c.applyOpts(opts)
 }
 
@@ -23,6 +31,35 @@ type Client struct {
 	apiClient api.ClientWithResponsesInterface
 	apiKey    string
 	baseURL   string
+
+	socketPath string
+	tlsConfig  *tls.Config
+	timeout    time.Duration
+
+	retryAttempts int
+	retryDelay    time.Duration
+	retries       atomic.Int64
+}
+
+// withUnixSocket switches the client to unix socket, if baseURL has unix:// or
+// unixs:// scheme, like syncthing formats its GUI address. Requests are sent to
+// localhost, using plain HTTP for unix:// and HTTPS for unixs://.
+func (self *Client) withUnixSocket() error {
+	u, err := url.Parse(self.baseURL)
+	if err != nil {
+		return fmt.Errorf("parse %q: %w", self.baseURL, err)
+	}
+
+	switch u.Scheme {
+	case "unix":
+		self.baseURL = "http://localhost/"
+	case "unixs":
+		self.baseURL = "https://localhost/"
+	default:
+		return nil
+	}
+	self.socketPath = u.Path
+	return nil
 }
 
 func (self *Client) WithKey(apiKey string) *Client {
@@ -35,8 +72,9 @@ func (self *Client) NewClientWithResponses(moreOpts ...api.ClientOption) error {
 		api.WithRequestEditorFn(self.withAPIKey),
 	}
 
+	// withRetry must be the last one, because it wraps configured HTTP client.
 	apiClient, err := api.NewClientWithResponses(self.baseURL,
-		slices.Concat(opts[:], moreOpts)...)
+		slices.Concat(opts[:], moreOpts, []api.ClientOption{self.withRetry})...)
 	if err != nil {
 		return fmt.Errorf("new client for %q: %w", self.baseURL, err)
 	}
@@ -58,22 +96,14 @@ func (self *Client) Health(ctx context.Context) error {
 	}
 
 	if r.JSON200 == nil {
-		return fmt.Errorf("health: %w", makeAPIError(r.JSONDefault,
-			r.Status(), r.Body))
+		return fmt.Errorf("health: %w", makeAPIError(r.HTTPResponse,
+			r.JSONDefault, r.Body))
 	} else if r.JSON200.Status != "OK" {
 		return fmt.Errorf("health: unexpected status: %v", r.JSON200.Status)
 	}
 	return nil
 }
 
-func makeAPIError(jsonErr *api.Error, status string, body []byte) error {
-	if jsonErr != nil {
-		return fmt.Errorf("unexpected syncthing error: %v", jsonErr.Error)
-	}
-	return fmt.Errorf("unexpected syncthing response: %v (%v)", status,
-		strings.TrimSpace(string(body)))
-}
-
 func (self *Client) Connections(ctx context.Context) (*api.Connections, error) {
 	r, err := self.apiClient.ConnectionsWithResponse(ctx)
 	if err != nil {
@@ -81,8 +111,8 @@ func (self *Client) Connections(ctx context.Context) (*api.Connections, error) {
 	}
 
 	if r.JSON200 == nil {
-		return nil, fmt.Errorf("connections: %w", makeAPIError(r.JSONDefault,
-			r.Status(), r.Body))
+		return nil, fmt.Errorf("connections: %w", makeAPIError(r.HTTPResponse,
+			r.JSONDefault, r.Body))
 	}
 	return r.JSON200, nil
 }
@@ -96,8 +126,8 @@ func (self *Client) Folders(ctx context.Context) ([]api.FolderConfiguration,
 	}
 
 	if r.JSON200 == nil {
-		return nil, fmt.Errorf("folders: %w", makeAPIError(r.JSONDefault,
-			r.Status(), r.Body))
+		return nil, fmt.Errorf("folders: %w", makeAPIError(r.HTTPResponse,
+			r.JSONDefault, r.Body))
 	}
 	return *r.JSON200, nil
 }
@@ -111,8 +141,8 @@ func (self *Client) Devices(ctx context.Context) ([]api.DeviceConfiguration,
 	}
 
 	if r.JSON200 == nil {
-		return nil, fmt.Errorf("devices: %w", makeAPIError(r.JSONDefault,
-			r.Status(), r.Body))
+		return nil, fmt.Errorf("devices: %w", makeAPIError(r.HTTPResponse,
+			r.JSONDefault, r.Body))
 	}
 	return *r.JSON200, nil
 }
@@ -126,8 +156,57 @@ func (self *Client) DeviceStats(ctx context.Context) (
 	}
 
 	if r.JSON200 == nil {
-		return nil, fmt.Errorf("device stats: %w", makeAPIError(r.JSONDefault,
-			r.Status(), r.Body))
+		return nil, fmt.Errorf("device stats: %w", makeAPIError(r.HTTPResponse,
+			r.JSONDefault, r.Body))
+	}
+	return *r.JSON200, nil
+}
+
+func (self *Client) FolderStats(ctx context.Context) (
+	map[string]api.FolderStatistics, error,
+) {
+	r, err := self.apiClient.FolderStatsWithResponse(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("folder stats request: %w", err)
+	}
+
+	if r.JSON200 == nil {
+		return nil, fmt.Errorf("folder stats: %w", makeAPIError(r.HTTPResponse,
+			r.JSONDefault, r.Body))
+	}
+	return *r.JSON200, nil
+}
+
+// PendingDevices returns remote devices, which have tried to connect, but
+// aren't configured yet, by their device IDs.
+func (self *Client) PendingDevices(ctx context.Context) (
+	map[string]api.PendingDevice, error,
+) {
+	r, err := self.apiClient.PendingDevicesWithResponse(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("pending devices request: %w", err)
+	}
+
+	if r.JSON200 == nil {
+		return nil, fmt.Errorf("pending devices: %w", makeAPIError(r.HTTPResponse,
+			r.JSONDefault, r.Body))
+	}
+	return *r.JSON200, nil
+}
+
+// PendingFolders returns folders, which remote devices have offered, but they
+// aren't shared with them yet, by their folder IDs.
+func (self *Client) PendingFolders(ctx context.Context) (
+	map[string]api.PendingFolder, error,
+) {
+	r, err := self.apiClient.PendingFoldersWithResponse(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("pending folders request: %w", err)
+	}
+
+	if r.JSON200 == nil {
+		return nil, fmt.Errorf("pending folders: %w", makeAPIError(r.HTTPResponse,
+			r.JSONDefault, r.Body))
 	}
 	return *r.JSON200, nil
 }
@@ -141,8 +220,23 @@ func (self *Client) Completion(ctx context.Context, folder, device string,
 	}
 
 	if r.JSON200 == nil {
-		return nil, fmt.Errorf("completion: %w", makeAPIError(r.JSONDefault,
-			r.Status(), r.Body))
+		return nil, fmt.Errorf("completion: %w", makeAPIError(r.HTTPResponse,
+			r.JSONDefault, r.Body))
+	}
+	return r.JSON200, nil
+}
+
+func (self *Client) FolderStatus(ctx context.Context, folder string,
+) (*api.FolderStatus, error) {
+	params := api.FolderStatusParams{Folder: folder}
+	r, err := self.apiClient.FolderStatusWithResponse(ctx, &params)
+	if err != nil {
+		return nil, fmt.Errorf("folder status request: %w", err)
+	}
+
+	if r.JSON200 == nil {
+		return nil, fmt.Errorf("folder status: %w", makeAPIError(r.HTTPResponse,
+			r.JSONDefault, r.Body))
 	}
 	return r.JSON200, nil
 }
@@ -156,8 +250,41 @@ func (self *Client) SystemStatus(ctx context.Context) (*api.SystemStatus,
 	}
 
 	if r.JSON200 == nil {
-		return nil, fmt.Errorf("system status: %w", makeAPIError(r.JSONDefault,
-			r.Status(), r.Body))
+		return nil, fmt.Errorf("system status: %w", makeAPIError(r.HTTPResponse,
+			r.JSONDefault, r.Body))
+	}
+	return r.JSON200, nil
+}
+
+// SystemUpgrade checks for a possible upgrade of syncthing. Syncthing returns
+// an error, if upgrades are disabled or it can't check them. It isn't retried,
+// because syncthing responds 503, if upgrades are unsupported.
+func (self *Client) SystemUpgrade(ctx context.Context) (*api.SystemUpgrade,
+	error,
+) {
+	r, err := self.apiClient.SystemUpgradeWithResponse(withoutRetry(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("system upgrade request: %w", err)
+	}
+
+	if r.JSON200 == nil {
+		return nil, fmt.Errorf("system upgrade: %w", makeAPIError(r.HTTPResponse,
+			r.JSONDefault, r.Body))
+	}
+	return r.JSON200, nil
+}
+
+func (self *Client) SystemVersion(ctx context.Context) (*api.SystemVersion,
+	error,
+) {
+	r, err := self.apiClient.SystemVersionWithResponse(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("system version request: %w", err)
+	}
+
+	if r.JSON200 == nil {
+		return nil, fmt.Errorf("system version: %w", makeAPIError(r.HTTPResponse,
+			r.JSONDefault, r.Body))
 	}
 	return r.JSON200, nil
 }
@@ -169,8 +296,8 @@ func (self *Client) SystemErrors(ctx context.Context) ([]api.LogLine, error) {
 	}
 
 	if r.JSON200 == nil {
-		return nil, fmt.Errorf("system errors: %w", makeAPIError(r.JSONDefault,
-			r.Status(), r.Body))
+		return nil, fmt.Errorf("system errors: %w", makeAPIError(r.HTTPResponse,
+			r.JSONDefault, r.Body))
 	}
 	return r.JSON200.Errors, nil
 }
@@ -184,8 +311,119 @@ func (self *Client) FolderErrors(ctx context.Context, folder string,
 	}
 
 	if r.JSON200 == nil {
-		return nil, fmt.Errorf("folder errors: %w", makeAPIError(r.JSONDefault,
-			r.Status(), r.Body))
+		return nil, fmt.Errorf("folder errors: %w", makeAPIError(r.HTTPResponse,
+			r.JSONDefault, r.Body))
 	}
 	return r.JSON200.Errors, nil
 }
+
+// FolderErrorsPage returns given page of folder errors, with perPage errors per
+// page. Pages are numbered from 1.
+func (self *Client) FolderErrorsPage(ctx context.Context, folder string,
+	page, perPage int,
+) (*api.FolderErrors, error) {
+	pageStr, perPageStr := strconv.Itoa(page), strconv.Itoa(perPage)
+	params := api.FolderErrorsParams{
+		Folder:  folder,
+		Page:    &pageStr,
+		Perpage: &perPageStr,
+	}
+	r, err := self.apiClient.FolderErrorsWithResponse(ctx, &params)
+	if err != nil {
+		return nil, fmt.Errorf("folder errors request: %w", err)
+	}
+
+	if r.JSON200 == nil {
+		return nil, fmt.Errorf("folder errors: %w", makeAPIError(r.HTTPResponse,
+			r.JSONDefault, r.Body))
+	}
+	return r.JSON200, nil
+}
+
+// AllFolderErrors returns an iterator over all errors of folder, which fetches
+// them page by page, with perPage errors per request. It stops after first
+// error.
+func (self *Client) AllFolderErrors(ctx context.Context, folder string,
+	perPage int,
+) iter.Seq2[*api.FileError, error] {
+	return allPages(perPage, func(page int) ([]api.FileError, int, error) {
+		r, err := self.FolderErrorsPage(ctx, folder, page, perPage)
+		if err != nil {
+			return nil, 0, err
+		}
+		return r.Errors, r.Page, nil
+	})
+}
+
+// LocalChangedPage returns given page of locally changed files of
+// receive-only folder, with perPage files per page. Pages are numbered from 1.
+func (self *Client) LocalChangedPage(ctx context.Context, folder string,
+	page, perPage int,
+) (*api.LocalChanged, error) {
+	pageStr, perPageStr := strconv.Itoa(page), strconv.Itoa(perPage)
+	params := api.LocalChangedParams{
+		Folder:  folder,
+		Page:    &pageStr,
+		Perpage: &perPageStr,
+	}
+	r, err := self.apiClient.LocalChangedWithResponse(ctx, &params)
+	if err != nil {
+		return nil, fmt.Errorf("local changed request: %w", err)
+	}
+
+	if r.JSON200 == nil {
+		return nil, fmt.Errorf("local changed: %w", makeAPIError(r.HTTPResponse,
+			r.JSONDefault, r.Body))
+	}
+	return r.JSON200, nil
+}
+
+// AllLocalChanged returns an iterator over all locally changed files of
+// receive-only folder, which fetches them page by page, with perPage files per
+// request. It stops after first error.
+func (self *Client) AllLocalChanged(ctx context.Context, folder string,
+	perPage int,
+) iter.Seq2[*api.FileInfo, error] {
+	return allPages(perPage, func(page int) ([]api.FileInfo, int, error) {
+		r, err := self.LocalChangedPage(ctx, folder, page, perPage)
+		if err != nil {
+			return nil, 0, err
+		}
+		return r.Files, r.Page, nil
+	})
+}
+
+// allPages returns an iterator over items of all pages, which fetches them
+// page by page, using fetch, with perPage items per page. fetch returns items
+// of given page and number of page, which server returned. It stops after last
+// page, first error, or if server returned another page, because it ignores
+// paging and returns the same items every time.
+func allPages[T any](perPage int, fetch func(page int) ([]T, int, error),
+) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		if perPage <= 0 {
+			yield(nil, fmt.Errorf("%v items per page isn't positive", perPage))
+			return
+		}
+
+		for page := 1; ; page++ {
+			items, gotPage, err := fetch(page)
+			if err != nil {
+				yield(nil, err)
+				return
+			} else if gotPage != page && page > 1 {
+				return
+			}
+
+			for i := range items {
+				if !yield(&items[i], nil) {
+					return
+				}
+			}
+
+			if gotPage != page || len(items) < perPage {
+				return
+			}
+		}
+	}
+}
@@ -3,9 +3,13 @@ package client
 import (
 	"encoding/json"
 	"errors"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -42,6 +46,54 @@ func TestNew(t *testing.T) {
 	assert.Same(t, apiClient, c.apiClient)
 }
 
+func TestNew_unixSocket(t *testing.T) {
+	_, err := New(":/")
+	require.ErrorContains(t, err, "parse \":/\"")
+
+	c, err := New("unix:///var/run/syncthing.sock")
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost/", c.baseURL)
+	assert.Equal(t, "/var/run/syncthing.sock", c.socketPath)
+
+	c, err = New("unixs:///var/run/syncthing.sock")
+	require.NoError(t, err)
+	assert.Equal(t, "https://localhost/", c.baseURL)
+	assert.Equal(t, "/var/run/syncthing.sock", c.socketPath)
+
+	socketPath := filepath.Join(t.TempDir(), "gui.sock")
+	c, err = New("unix://" + socketPath)
+	require.NoError(t, err)
+	require.ErrorContains(t, c.Health(t.Context()), "dial unix socket")
+
+	srv := newTestUnixServer(t, socketPath)
+	srv.Start()
+	c, err = New("unix://" + socketPath)
+	require.NoError(t, err)
+	require.NoError(t, c.Health(t.Context()))
+
+	socketPath = filepath.Join(t.TempDir(), "gui.sock")
+	srv = newTestUnixServer(t, socketPath)
+	srv.StartTLS()
+	c, err = New("unixs://"+socketPath, WithInsecureSkipVerify())
+	require.NoError(t, err)
+	require.NoError(t, c.Health(t.Context()))
+}
+
+func newTestUnixServer(t *testing.T, socketPath string) *httptest.Server {
+	l, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, err := w.Write([]byte(`{ "status": "OK" }`))
+			assert.NoError(t, err)
+		}))
+	srv.Listener = l
+	t.Cleanup(srv.Close)
+	return srv
+}
+
 func TestClient_NewClientWithResponses(t *testing.T) {
 	c := Client{}
 	wantErr := errors.New("test error")
@@ -79,18 +131,6 @@ func TestClient_withAPIKey(t *testing.T) {
 	require.NoError(t, c.WithKey(key).Health(t.Context()))
 }
 
-func TestMakeAPIError(t *testing.T) {
-	err := makeAPIError(nil, "404 not found", []byte("some body\n"))
-	t.Log(err)
-	require.ErrorContains(t, err,
-		"unexpected syncthing response: 404 not found (some body)")
-
-	jsonErr := api.Error{Error: "some error"}
-	err = makeAPIError(&jsonErr, "", nil)
-	t.Log(err)
-	require.ErrorContains(t, err, "unexpected syncthing error: some error")
-}
-
 type testHttpDoer struct {
 	do func(req *http.Request) (*http.Response, error)
 }
@@ -567,6 +607,167 @@ func TestClient_DeviceStats(t *testing.T) {
 	}
 }
 
+func TestClient_FolderStats(t *testing.T) {
+	tests := []struct {
+		name        string
+		statusCode  int
+		contentType string
+		body        string
+		assertErr   func(t *testing.T, err error)
+	}{
+		{
+			name:        "OK",
+			statusCode:  http.StatusOK,
+			contentType: "application/json",
+			body: `
+{
+  "default": {
+    "lastFile": {
+      "at": "2024-03-28T20:15:11+01:00",
+      "filename": "some file",
+      "deleted": false
+    },
+    "lastScan": "2024-03-29T06:02:42+01:00"
+  }
+}`,
+		},
+		{
+			name:        "has error",
+			statusCode:  http.StatusInternalServerError,
+			contentType: "application/json",
+			body:        `{ "error": "some error message" }`,
+			assertErr: func(t *testing.T, err error) {
+				assert.ErrorContains(t, err,
+					"folder stats: unexpected syncthing error: some error message")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, tt.contentType, tt.statusCode, tt.body)
+			stats, err := c.FolderStats(t.Context())
+			if tt.assertErr != nil {
+				t.Log(err)
+				require.Error(t, err)
+				tt.assertErr(t, err)
+			} else {
+				require.NoError(t, err)
+				var want map[string]api.FolderStatistics
+				require.NoError(t, json.Unmarshal([]byte(tt.body), &want))
+				assert.Equal(t, want, stats)
+			}
+		})
+	}
+}
+
+func TestClient_PendingDevices(t *testing.T) {
+	tests := []struct {
+		name        string
+		statusCode  int
+		contentType string
+		body        string
+		assertErr   func(t *testing.T, err error)
+	}{
+		{
+			name:        "OK",
+			statusCode:  http.StatusOK,
+			contentType: "application/json",
+			body: `
+{
+  "XXXXXX1-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXX1": {
+    "time": "2024-03-28T20:15:11+01:00",
+    "name": "some device",
+    "address": "192.168.1.2:22000"
+  }
+}`,
+		},
+		{
+			name:        "has error",
+			statusCode:  http.StatusInternalServerError,
+			contentType: "application/json",
+			body:        `{ "error": "some error message" }`,
+			assertErr: func(t *testing.T, err error) {
+				assert.ErrorContains(t, err,
+					"pending devices: unexpected syncthing error: some error message")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, tt.contentType, tt.statusCode, tt.body)
+			devices, err := c.PendingDevices(t.Context())
+			if tt.assertErr != nil {
+				t.Log(err)
+				require.Error(t, err)
+				tt.assertErr(t, err)
+			} else {
+				require.NoError(t, err)
+				var want map[string]api.PendingDevice
+				require.NoError(t, json.Unmarshal([]byte(tt.body), &want))
+				assert.Equal(t, want, devices)
+			}
+		})
+	}
+}
+
+func TestClient_PendingFolders(t *testing.T) {
+	tests := []struct {
+		name        string
+		statusCode  int
+		contentType string
+		body        string
+		assertErr   func(t *testing.T, err error)
+	}{
+		{
+			name:        "OK",
+			statusCode:  http.StatusOK,
+			contentType: "application/json",
+			body: `
+{
+  "xxxxx-yyyyy": {
+    "offeredBy": {
+      "XXXXXX1-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXX1": {
+        "time": "2024-03-28T20:15:11+01:00",
+        "label": "Some Folder",
+        "receiveEncrypted": false,
+        "remoteEncrypted": false
+      }
+    }
+  }
+}`,
+		},
+		{
+			name:        "has error",
+			statusCode:  http.StatusInternalServerError,
+			contentType: "application/json",
+			body:        `{ "error": "some error message" }`,
+			assertErr: func(t *testing.T, err error) {
+				assert.ErrorContains(t, err,
+					"pending folders: unexpected syncthing error: some error message")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, tt.contentType, tt.statusCode, tt.body)
+			folders, err := c.PendingFolders(t.Context())
+			if tt.assertErr != nil {
+				t.Log(err)
+				require.Error(t, err)
+				tt.assertErr(t, err)
+			} else {
+				require.NoError(t, err)
+				var want map[string]api.PendingFolder
+				require.NoError(t, json.Unmarshal([]byte(tt.body), &want))
+				assert.Equal(t, want, folders)
+			}
+		})
+	}
+}
+
 func TestClient_Completion(t *testing.T) {
 	const folder = "default"
 	const device = "XXXXXX1-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXX1"
@@ -842,6 +1043,96 @@ func TestClient_FolderErrors(t *testing.T) {
 	}
 }
 
+func TestClient_FolderStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		statusCode  int
+		contentType string
+		body        string
+		assertErr   func(t *testing.T, err error)
+	}{
+		{
+			name:        "OK",
+			statusCode:  http.StatusOK,
+			contentType: "application/json",
+			body: `
+{
+  "errors": 0,
+  "pullErrors": 0,
+  "globalBytes": 26996726,
+  "globalDeleted": 2,
+  "globalDirectories": 4,
+  "globalFiles": 35,
+  "globalSymlinks": 0,
+  "globalTotalItems": 41,
+  "ignorePatterns": false,
+  "inSyncBytes": 26996726,
+  "inSyncFiles": 35,
+  "localBytes": 26996726,
+  "localDeleted": 2,
+  "localDirectories": 4,
+  "localFiles": 35,
+  "localSymlinks": 0,
+  "localTotalItems": 41,
+  "needBytes": 0,
+  "needDeletes": 0,
+  "needDirectories": 0,
+  "needFiles": 0,
+  "needSymlinks": 0,
+  "needTotalItems": 0,
+  "receiveOnlyChangedBytes": 0,
+  "receiveOnlyChangedDeletes": 0,
+  "receiveOnlyChangedDirectories": 0,
+  "receiveOnlyChangedFiles": 0,
+  "receiveOnlyChangedSymlinks": 0,
+  "receiveOnlyTotalItems": 0,
+  "sequence": 145,
+  "state": "idle",
+  "stateChanged": "2024-03-28T20:15:11+01:00",
+  "error": "",
+  "version": 145,
+  "watchError": ""
+}`,
+		},
+		{
+			name:        "empty body",
+			statusCode:  http.StatusOK,
+			contentType: "application/json",
+			assertErr: func(t *testing.T, err error) {
+				var syntaxErr *json.SyntaxError
+				assert.ErrorAs(t, err, &syntaxErr)
+			},
+		},
+		{
+			name:        "has error",
+			statusCode:  http.StatusInternalServerError,
+			contentType: "application/json",
+			body:        `{ "error": "some error message" }`,
+			assertErr: func(t *testing.T, err error) {
+				assert.ErrorContains(t, err,
+					"folder status: unexpected syncthing error: some error message")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, tt.contentType, tt.statusCode, tt.body)
+			status, err := c.FolderStatus(t.Context(), "default")
+			if tt.assertErr != nil {
+				t.Log(err)
+				require.Error(t, err)
+				tt.assertErr(t, err)
+			} else {
+				require.NoError(t, err)
+				var want api.FolderStatus
+				require.NoError(t, json.Unmarshal([]byte(tt.body), &want))
+				assert.Equal(t, &want, status)
+			}
+		})
+	}
+}
+
 func TestClient_SystemStatus(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -948,3 +1239,356 @@ func TestClient_SystemStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_SystemUpgrade(t *testing.T) {
+	tests := []struct {
+		name        string
+		statusCode  int
+		contentType string
+		body        string
+		assertErr   func(t *testing.T, err error)
+	}{
+		{
+			name:        "OK",
+			statusCode:  http.StatusOK,
+			contentType: "application/json",
+			body: `
+{
+  "latest": "v1.27.5",
+  "majorNewer": false,
+  "newer": true,
+  "running": "v1.27.4"
+}`,
+		},
+		{
+			name:        "upgrade unsupported",
+			statusCode:  http.StatusInternalServerError,
+			contentType: "text/plain",
+			body:        "upgrade unsupported",
+			assertErr: func(t *testing.T, err error) {
+				var apiErr *APIError
+				require.ErrorAs(t, err, &apiErr)
+				assert.Equal(t, "upgrade unsupported", apiErr.Body)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, tt.contentType, tt.statusCode, tt.body)
+			upgrade, err := c.SystemUpgrade(t.Context())
+			if tt.assertErr != nil {
+				t.Log(err)
+				require.Error(t, err)
+				tt.assertErr(t, err)
+			} else {
+				require.NoError(t, err)
+				var want api.SystemUpgrade
+				require.NoError(t, json.Unmarshal([]byte(tt.body), &want))
+				assert.Equal(t, &want, upgrade)
+			}
+		})
+	}
+}
+
+func TestClient_SystemVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		statusCode  int
+		contentType string
+		body        string
+		assertErr   func(t *testing.T, err error)
+	}{
+		{
+			name:        "OK",
+			statusCode:  http.StatusOK,
+			contentType: "application/json",
+			body: `
+{
+  "arch": "amd64",
+  "codename": "Gold Grasshopper",
+  "date": "2024-03-23T09:32:16Z",
+  "isBeta": false,
+  "isCandidate": false,
+  "isRelease": true,
+  "longVersion": "syncthing v1.27.4 \"Gold Grasshopper\" (go1.22.1 freebsd-amd64) root@freebsd 2024-03-23 09:32:16 UTC",
+  "os": "freebsd",
+  "stamp": "1711186336",
+  "tags": [],
+  "user": "root",
+  "version": "v1.27.4"
+}`,
+		},
+		{
+			name:        "has error",
+			statusCode:  http.StatusInternalServerError,
+			contentType: "application/json",
+			body:        `{ "error": "some error message" }`,
+			assertErr: func(t *testing.T, err error) {
+				assert.ErrorContains(t, err,
+					"system version: unexpected syncthing error: some error message")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, tt.contentType, tt.statusCode, tt.body)
+			version, err := c.SystemVersion(t.Context())
+			if tt.assertErr != nil {
+				t.Log(err)
+				require.Error(t, err)
+				tt.assertErr(t, err)
+			} else {
+				require.NoError(t, err)
+				var want api.SystemVersion
+				require.NoError(t, json.Unmarshal([]byte(tt.body), &want))
+				assert.Equal(t, &want, version)
+			}
+		})
+	}
+}
+
+func TestClient_AllFolderErrors(t *testing.T) {
+	fileErrors := []api.FileError{
+		{Error: "error1", Path: "path1"},
+		{Error: "error2", Path: "path2"},
+		{Error: "error3", Path: "path3"},
+	}
+
+	var pages []string
+	httpClient := testHttpDoer{
+		func(req *http.Request) (*http.Response, error) {
+			q := req.URL.Query()
+			assert.Equal(t, "default", q.Get("folder"))
+			assert.Equal(t, "2", q.Get("perpage"))
+			pages = append(pages, q.Get("page"))
+
+			page, err := strconv.Atoi(q.Get("page"))
+			require.NoError(t, err)
+			first := min((page-1)*2, len(fileErrors))
+			b, err := json.Marshal(api.FolderErrors{
+				Errors:  fileErrors[first:min(first+2, len(fileErrors))],
+				Folder:  "default",
+				Page:    page,
+				Perpage: 2,
+			})
+			require.NoError(t, err)
+
+			r := httptest.NewRecorder()
+			r.Header().Set("Content-Type", "application/json")
+			r.WriteHeader(http.StatusOK)
+			_, err = r.Write(b)
+			require.NoError(t, err)
+			return r.Result(), nil
+		},
+	}
+
+	c, err := New("/", func(self *Client) error {
+		return self.NewClientWithResponses(api.WithHTTPClient(&httpClient))
+	})
+	require.NoError(t, err)
+
+	var got []api.FileError
+	for fileErr, err := range c.AllFolderErrors(t.Context(), "default", 2) {
+		require.NoError(t, err)
+		got = append(got, *fileErr)
+	}
+	assert.Equal(t, fileErrors, got)
+	assert.Equal(t, []string{"1", "2"}, pages)
+
+	pages = nil
+	for fileErr, err := range c.AllFolderErrors(t.Context(), "default", 2) {
+		require.NoError(t, err)
+		assert.Equal(t, fileErrors[0], *fileErr)
+		break
+	}
+	assert.Equal(t, []string{"1"}, pages)
+
+	c = newTestClient(t, "text/plain", http.StatusNotFound, "")
+	var numErrors int
+	for fileErr, err := range c.AllFolderErrors(t.Context(), "default", 2) {
+		require.ErrorContains(t, err, "folder errors: ")
+		assert.Nil(t, fileErr)
+		numErrors++
+	}
+	assert.Equal(t, 1, numErrors)
+}
+
+func TestClient_AllFolderErrors_noPaging(t *testing.T) {
+	fileErrors := []api.FileError{
+		{Error: "error1", Path: "path1"},
+		{Error: "error2", Path: "path2"},
+	}
+
+	tests := []struct {
+		name  string
+		page  int
+		pages []string
+	}{
+		{name: "same page", page: 1, pages: []string{"1", "2"}},
+		{name: "without page", page: 0, pages: []string{"1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pages []string
+			httpClient := testHttpDoer{
+				func(req *http.Request) (*http.Response, error) {
+					pages = append(pages, req.URL.Query().Get("page"))
+					b, err := json.Marshal(api.FolderErrors{
+						Errors: fileErrors,
+						Folder: "default",
+						Page:   tt.page,
+					})
+					require.NoError(t, err)
+
+					r := httptest.NewRecorder()
+					r.Header().Set("Content-Type", "application/json")
+					r.WriteHeader(http.StatusOK)
+					_, err = r.Write(b)
+					require.NoError(t, err)
+					return r.Result(), nil
+				},
+			}
+
+			c, err := New("/", func(self *Client) error {
+				return self.NewClientWithResponses(api.WithHTTPClient(&httpClient))
+			})
+			require.NoError(t, err)
+
+			var got []api.FileError
+			for fileErr, err := range c.AllFolderErrors(t.Context(), "default", 2) {
+				require.NoError(t, err)
+				got = append(got, *fileErr)
+			}
+			assert.Equal(t, fileErrors, got)
+			assert.Equal(t, tt.pages, pages)
+		})
+	}
+}
+
+func TestClient_AllFolderErrors_perPage(t *testing.T) {
+	c := newTestClient(t, "application/json", http.StatusOK, "{}")
+	for _, perPage := range []int{0, -1} {
+		var numErrors int
+		for fileErr, err := range c.AllFolderErrors(t.Context(), "default",
+			perPage) {
+			require.ErrorContains(t, err, "per page isn't positive")
+			assert.Nil(t, fileErr)
+			numErrors++
+		}
+		assert.Equal(t, 1, numErrors)
+	}
+}
+
+func TestClient_AllLocalChanged(t *testing.T) {
+	modified := time.Date(2024, 3, 28, 20, 15, 11, 0, time.UTC)
+	files := []api.FileInfo{
+		{Name: "file1", Type: "FILE_INFO_TYPE_FILE", Size: 1, Modified: modified},
+		{Name: "dir2", Type: "FILE_INFO_TYPE_DIRECTORY", Modified: modified},
+		{Name: "file3", Type: "FILE_INFO_TYPE_FILE", Deleted: true},
+	}
+
+	var pages []string
+	httpClient := testHttpDoer{
+		func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "/rest/db/localchanged", req.URL.Path)
+			q := req.URL.Query()
+			assert.Equal(t, "default", q.Get("folder"))
+			assert.Equal(t, "2", q.Get("perpage"))
+			pages = append(pages, q.Get("page"))
+
+			page, err := strconv.Atoi(q.Get("page"))
+			require.NoError(t, err)
+			first := min((page-1)*2, len(files))
+			b, err := json.Marshal(api.LocalChanged{
+				Files:   files[first:min(first+2, len(files))],
+				Page:    page,
+				Perpage: 2,
+			})
+			require.NoError(t, err)
+
+			r := httptest.NewRecorder()
+			r.Header().Set("Content-Type", "application/json")
+			r.WriteHeader(http.StatusOK)
+			_, err = r.Write(b)
+			require.NoError(t, err)
+			return r.Result(), nil
+		},
+	}
+
+	c, err := New("/", func(self *Client) error {
+		return self.NewClientWithResponses(api.WithHTTPClient(&httpClient))
+	})
+	require.NoError(t, err)
+
+	var got []api.FileInfo
+	for file, err := range c.AllLocalChanged(t.Context(), "default", 2) {
+		require.NoError(t, err)
+		got = append(got, *file)
+	}
+	assert.Equal(t, files, got)
+	assert.Equal(t, []string{"1", "2"}, pages)
+
+	pages = nil
+	for file, err := range c.AllLocalChanged(t.Context(), "default", 2) {
+		require.NoError(t, err)
+		assert.Equal(t, files[0], *file)
+		break
+	}
+	assert.Equal(t, []string{"1"}, pages)
+
+	c = newTestClient(t, "text/plain", http.StatusNotFound, "")
+	var numErrors int
+	for file, err := range c.AllLocalChanged(t.Context(), "default", 2) {
+		require.ErrorContains(t, err, "local changed: ")
+		assert.Nil(t, file)
+		numErrors++
+	}
+	assert.Equal(t, 1, numErrors)
+}
+
+func TestClient_AllLocalChanged_noPaging(t *testing.T) {
+	files := []api.FileInfo{
+		{Name: "file1", Type: "FILE_INFO_TYPE_FILE"},
+		{Name: "file2", Type: "FILE_INFO_TYPE_FILE"},
+	}
+
+	var pages []string
+	httpClient := testHttpDoer{
+		func(req *http.Request) (*http.Response, error) {
+			pages = append(pages, req.URL.Query().Get("page"))
+			b, err := json.Marshal(api.LocalChanged{Files: files, Page: 1})
+			require.NoError(t, err)
+
+			r := httptest.NewRecorder()
+			r.Header().Set("Content-Type", "application/json")
+			r.WriteHeader(http.StatusOK)
+			_, err = r.Write(b)
+			require.NoError(t, err)
+			return r.Result(), nil
+		},
+	}
+
+	c, err := New("/", func(self *Client) error {
+		return self.NewClientWithResponses(api.WithHTTPClient(&httpClient))
+	})
+	require.NoError(t, err)
+
+	var got []api.FileInfo
+	for file, err := range c.AllLocalChanged(t.Context(), "default", 2) {
+		require.NoError(t, err)
+		got = append(got, *file)
+	}
+	assert.Equal(t, files, got)
+	assert.Equal(t, []string{"1", "2"}, pages)
+
+	var numErrors int
+	for file, err := range c.AllLocalChanged(t.Context(), "default", 0) {
+		require.ErrorContains(t, err, "per page isn't positive")
+		assert.Nil(t, file)
+		numErrors++
+	}
+	assert.Equal(t, 1, numErrors)
+}
@@ -0,0 +1,93 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/dsh2dsh/check_syncthing/client/api"
+)
+
+// LoadEventCursor loads [EventCursor] from state file fname. It returns empty
+// cursor, if fname doesn't exist yet.
+func LoadEventCursor(fname string) (*EventCursor, error) {
+	c := &EventCursor{fname: fname}
+	b, err := os.ReadFile(fname)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("read events state: %w", err)
+	}
+
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("parse events state %q: %w", fname, err)
+	}
+	return c, nil
+}
+
+// EventCursor remembers ID of last processed event between plugin invocations,
+// so every invocation processes new events only.
+//
+// Syncthing numbers events from 1 after every restart, so the cursor
+// remembers start time of syncthing too and starts from the beginning, if
+// syncthing restarted. Every set of event types has its own numbering of
+// events, so the cursor starts from the beginning, if types changed too.
+type EventCursor struct {
+	LastID    int       `json:"lastId"`
+	StartTime time.Time `json:"startTime"`
+	Types     []string  `json:"types,omitempty"`
+
+	fname string
+}
+
+// Since returns ID of last processed event of given types of syncthing started
+// at startTime, suitable for [EventsQuery.Since]. It resets the cursor, if
+// startTime or types changed, and returns 0.
+func (self *EventCursor) Since(startTime time.Time, types []string) int {
+	if !self.StartTime.Equal(startTime) || !slices.Equal(self.Types, types) {
+		self.LastID, self.StartTime = 0, startTime
+		self.Types = slices.Clone(types)
+	}
+	return self.LastID
+}
+
+// Update moves the cursor to the last of processed events.
+func (self *EventCursor) Update(events []api.Event) {
+	for i := range events {
+		self.LastID = max(self.LastID, events[i].Id)
+	}
+}
+
+// Save atomically writes the cursor to its state file.
+func (self *EventCursor) Save() error {
+	return saveState(self.fname, "events state", self)
+}
+
+// saveState atomically writes v as JSON to state file fname. what describes
+// the state in errors.
+func saveState(fname, what string, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshal %v: %w", what, err)
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(fname), "."+filepath.Base(fname)+".*")
+	if err != nil {
+		return fmt.Errorf("create %v: %w", what, err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return fmt.Errorf("write %v %q: %w", what, f.Name(), err)
+	} else if err := f.Close(); err != nil {
+		return fmt.Errorf("close %v %q: %w", what, f.Name(), err)
+	} else if err := os.Rename(f.Name(), fname); err != nil {
+		return fmt.Errorf("rename %v: %w", what, err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dsh2dsh/check_syncthing/client/api"
+)
+
+func TestEventCursor(t *testing.T) {
+	fname := filepath.Join(t.TempDir(), "events.json")
+	c, err := LoadEventCursor(fname)
+	require.NoError(t, err)
+	assert.Zero(t, c.LastID)
+	assert.True(t, c.StartTime.IsZero())
+
+	startTime := time.Date(2024, 3, 28, 20, 15, 11, 0, time.UTC)
+	assert.Zero(t, c.Since(startTime, nil))
+	c.Update([]api.Event{{Id: 2}, {Id: 3}})
+	assert.Equal(t, 3, c.Since(startTime, nil))
+	require.NoError(t, c.Save())
+
+	entries, err := os.ReadDir(filepath.Dir(fname))
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "no temporary files")
+
+	c, err = LoadEventCursor(fname)
+	require.NoError(t, err)
+	assert.Equal(t, 3, c.Since(startTime, nil))
+	c.Update(nil)
+	assert.Equal(t, 3, c.Since(startTime, nil))
+
+	assert.Zero(t, c.Since(startTime.Add(time.Minute), nil),
+		"syncthing restarted")
+	assert.Equal(t, startTime.Add(time.Minute), c.StartTime)
+
+	types := []string{EventFolderErrors}
+	c.Update([]api.Event{{Id: 5}})
+	assert.Zero(t, c.Since(startTime.Add(time.Minute), types), "types changed")
+	assert.Equal(t, types, c.Types)
+	c.Update([]api.Event{{Id: 2}})
+	require.NoError(t, c.Save())
+
+	c, err = LoadEventCursor(fname)
+	require.NoError(t, err)
+	assert.Equal(t, 2, c.Since(startTime.Add(time.Minute), types))
+}
+
+func TestLoadEventCursor_errors(t *testing.T) {
+	fname := filepath.Join(t.TempDir(), "events.json")
+	require.NoError(t, os.WriteFile(fname, []byte("{"), 0o600))
+	_, err := LoadEventCursor(fname)
+	require.ErrorContains(t, err, "parse events state")
+
+	_, err = LoadEventCursor(t.TempDir())
+	require.ErrorContains(t, err, "read events state")
+
+	c, err := LoadEventCursor(filepath.Join(t.TempDir(), "nodir", "events.json"))
+	require.NoError(t, err)
+	require.ErrorContains(t, c.Save(), "create events state")
+}
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/dsh2dsh/check_syncthing/client/api"
+)
+
+func makeAPIError(resp *http.Response, jsonErr *api.Error, body []byte,
+) *APIError {
+	err := &APIError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+	}
+	if resp.Request != nil {
+		err.Endpoint = resp.Request.URL.Path
+	}
+
+	if jsonErr != nil {
+		err.Message = jsonErr.Error
+	} else {
+		err.Body = strings.TrimSpace(string(body))
+	}
+	return err
+}
+
+// APIError is an unexpected response of syncthing REST API.
+type APIError struct {
+	// Endpoint is URL path of the request, like /rest/system/status.
+	Endpoint string
+
+	StatusCode int
+	Status     string
+
+	// Message is error text from JSON response of syncthing.
+	Message string
+	// Body is response body, if syncthing responded without JSON error.
+	Body string
+}
+
+func (self *APIError) Error() string {
+	if self.Message != "" {
+		return "unexpected syncthing error: " + self.Message
+	}
+	return fmt.Sprintf("unexpected syncthing response: %v (%v)", self.Status,
+		self.Body)
+}
+
+// Unauthorized returns true, if syncthing rejected API key.
+func (self *APIError) Unauthorized() bool {
+	return self.StatusCode == http.StatusUnauthorized ||
+		self.StatusCode == http.StatusForbidden
+}
+
+// NotFound returns true, if syncthing has no such endpoint or object.
+func (self *APIError) NotFound() bool {
+	return self.StatusCode == http.StatusNotFound
+}
+
+// ServerError returns true, if syncthing failed handling the request.
+func (self *APIError) ServerError() bool {
+	return self.StatusCode >= http.StatusInternalServerError
+}
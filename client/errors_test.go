@@ -0,0 +1,66 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dsh2dsh/check_syncthing/client/api"
+)
+
+func TestMakeAPIError(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusNotFound,
+		Status:     "404 not found",
+		Request:    httptest.NewRequest(http.MethodGet, "/rest/foo?bar=1", nil),
+	}
+	err := makeAPIError(resp, nil, []byte("some body\n"))
+	t.Log(err)
+	require.ErrorContains(t, err,
+		"unexpected syncthing response: 404 not found (some body)")
+	assert.Equal(t, &APIError{
+		Endpoint:   "/rest/foo",
+		StatusCode: http.StatusNotFound,
+		Status:     "404 not found",
+		Body:       "some body",
+	}, err)
+
+	jsonErr := api.Error{Error: "some error"}
+	resp = &http.Response{
+		StatusCode: http.StatusInternalServerError,
+		Status:     "500 Internal Server Error",
+	}
+	err = makeAPIError(resp, &jsonErr, nil)
+	t.Log(err)
+	require.ErrorContains(t, err, "unexpected syncthing error: some error")
+	assert.Empty(t, err.Endpoint)
+	assert.Equal(t, "some error", err.Message)
+}
+
+func TestAPIError_status(t *testing.T) {
+	tests := []struct {
+		statusCode   int
+		unauthorized bool
+		notFound     bool
+		serverError  bool
+	}{
+		{statusCode: http.StatusBadRequest},
+		{statusCode: http.StatusUnauthorized, unauthorized: true},
+		{statusCode: http.StatusForbidden, unauthorized: true},
+		{statusCode: http.StatusNotFound, notFound: true},
+		{statusCode: http.StatusInternalServerError, serverError: true},
+		{statusCode: http.StatusServiceUnavailable, serverError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.statusCode), func(t *testing.T) {
+			err := APIError{StatusCode: tt.statusCode}
+			assert.Equal(t, tt.unauthorized, err.Unauthorized())
+			assert.Equal(t, tt.notFound, err.NotFound())
+			assert.Equal(t, tt.serverError, err.ServerError())
+		})
+	}
+}
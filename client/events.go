@@ -0,0 +1,128 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dsh2dsh/check_syncthing/client/api"
+)
+
+// Types of syncthing events, see https://docs.syncthing.net/dev/events.html
+const (
+	EventConfigSaved          = "ConfigSaved"
+	EventDeviceConnected      = "DeviceConnected"
+	EventDeviceDisconnected   = "DeviceDisconnected"
+	EventDevicePaused         = "DevicePaused"
+	EventDeviceRejected       = "DeviceRejected"
+	EventFolderErrors         = "FolderErrors"
+	EventFolderPaused         = "FolderPaused"
+	EventFolderRejected       = "FolderRejected"
+	EventFolderResumed        = "FolderResumed"
+	EventLocalChangeDetected  = "LocalChangeDetected"
+	EventRemoteChangeDetected = "RemoteChangeDetected"
+	EventStarting             = "Starting"
+	EventStateChanged         = "StateChanged"
+)
+
+// EventsQuery selects events returned by [Client.Events] and
+// [Client.DiskEvents].
+type EventsQuery struct {
+	// Types of events to return. Empty means all types, except disk events.
+	// It's ignored by [Client.DiskEvents].
+	Types []string
+
+	// Since returns events with ID greater than Since.
+	Since int
+
+	// Limit returns only last Limit events. Zero means all events.
+	Limit int
+
+	// Timeout is how long syncthing waits for new events, if there are no
+	// events since Since. Zero means it responds immediately. It must be less
+	// than HTTP timeout of the client.
+	Timeout time.Duration
+}
+
+func (self *EventsQuery) since() *string {
+	s := strconv.Itoa(self.Since)
+	return &s
+}
+
+func (self *EventsQuery) limit() *string {
+	if self.Limit <= 0 {
+		return nil
+	}
+	s := strconv.Itoa(self.Limit)
+	return &s
+}
+
+func (self *EventsQuery) timeout() *string {
+	s := strconv.Itoa(int(self.Timeout / time.Second))
+	return &s
+}
+
+func (self *EventsQuery) types() *string {
+	if len(self.Types) == 0 {
+		return nil
+	}
+	s := strings.Join(self.Types, ",")
+	return &s
+}
+
+// Events returns events selected by q.
+func (self *Client) Events(ctx context.Context, q *EventsQuery) ([]api.Event,
+	error,
+) {
+	params := api.EventsParams{
+		Events:  q.types(),
+		Since:   q.since(),
+		Limit:   q.limit(),
+		Timeout: q.timeout(),
+	}
+	r, err := self.apiClient.EventsWithResponse(ctx, &params)
+	if err != nil {
+		return nil, fmt.Errorf("events request: %w", err)
+	}
+
+	if r.JSON200 == nil {
+		return nil, fmt.Errorf("events: %w", makeAPIError(r.HTTPResponse,
+			r.JSONDefault, r.Body))
+	}
+	return *r.JSON200, nil
+}
+
+// DiskEvents returns LocalChangeDetected and RemoteChangeDetected events
+// selected by q. Disk events have their own sequence of IDs.
+func (self *Client) DiskEvents(ctx context.Context, q *EventsQuery,
+) ([]api.Event, error) {
+	params := api.DiskEventsParams{
+		Since:   q.since(),
+		Limit:   q.limit(),
+		Timeout: q.timeout(),
+	}
+	r, err := self.apiClient.DiskEventsWithResponse(ctx, &params)
+	if err != nil {
+		return nil, fmt.Errorf("disk events request: %w", err)
+	}
+
+	if r.JSON200 == nil {
+		return nil, fmt.Errorf("disk events: %w", makeAPIError(r.HTTPResponse,
+			r.JSONDefault, r.Body))
+	}
+	return *r.JSON200, nil
+}
+
+// DecodeEventData decodes data of event e into T, like
+// [api.FolderErrorsEventData] for FolderErrors event.
+func DecodeEventData[T any](e *api.Event) (*T, error) {
+	data := new(T)
+	if err := json.Unmarshal(e.Data, data); err != nil {
+		return nil, fmt.Errorf("decode data of %v event id=%v: %w", e.Type, e.Id,
+			err)
+	}
+	return data, nil
+}
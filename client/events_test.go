@@ -0,0 +1,141 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dsh2dsh/check_syncthing/client/api"
+)
+
+const testEventsJSON = `[
+  {
+    "id": 2,
+    "globalID": 12,
+    "type": "DeviceDisconnected",
+    "time": "2024-03-28T20:15:11+01:00",
+    "data": {
+      "error": "unexpected EOF",
+      "id": "XXXXXX2-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX"
+    }
+  },
+  {
+    "id": 3,
+    "globalID": 13,
+    "type": "FolderErrors",
+    "time": "2024-03-28T20:15:12+01:00",
+    "data": {
+      "folder": "default",
+      "errors": [ { "error": "some error", "path": "/some file path" } ]
+    }
+  }
+]`
+
+func newTestEventsClient(t *testing.T, query *url.Values) *Client {
+	httpClient := testHttpDoer{
+		func(req *http.Request) (*http.Response, error) {
+			*query = req.URL.Query()
+			r := httptest.NewRecorder()
+			r.Header().Set("Content-Type", "application/json")
+			r.WriteHeader(http.StatusOK)
+			_, err := r.WriteString(testEventsJSON)
+			require.NoError(t, err)
+			return r.Result(), nil
+		},
+	}
+
+	c, err := New("/", func(self *Client) error {
+		return self.NewClientWithResponses(api.WithHTTPClient(&httpClient))
+	})
+	require.NoError(t, err)
+	return c
+}
+
+func TestClient_Events(t *testing.T) {
+	var query url.Values
+	c := newTestEventsClient(t, &query)
+
+	events, err := c.Events(t.Context(), &EventsQuery{})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, url.Values{"since": {"0"}, "timeout": {"0"}}, query)
+
+	events, err = c.Events(t.Context(), &EventsQuery{
+		Types: []string{EventDeviceDisconnected, EventFolderErrors},
+		Since: 1,
+		Limit: 10,
+		// Syncthing accepts seconds only.
+		Timeout: 1500 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, url.Values{
+		"events":  {"DeviceDisconnected,FolderErrors"},
+		"since":   {"1"},
+		"limit":   {"10"},
+		"timeout": {"1"},
+	}, query)
+
+	assert.Equal(t, 3, events[1].Id)
+	assert.Equal(t, EventFolderErrors, events[1].Type)
+
+	c = newTestClient(t, "application/json", http.StatusInternalServerError,
+		`{ "error": "some error" }`)
+	_, err = c.Events(t.Context(), &EventsQuery{})
+	require.ErrorContains(t, err, "events: unexpected syncthing error: some error")
+}
+
+func TestClient_DiskEvents(t *testing.T) {
+	var query url.Values
+	c := newTestEventsClient(t, &query)
+
+	events, err := c.DiskEvents(t.Context(), &EventsQuery{
+		Types: []string{EventDeviceDisconnected},
+		Since: 1,
+		Limit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, url.Values{
+		"since":   {"1"},
+		"limit":   {"10"},
+		"timeout": {"0"},
+	}, query)
+
+	c = newTestClient(t, "text/plain", http.StatusNotFound, "")
+	_, err = c.DiskEvents(t.Context(), &EventsQuery{})
+	require.ErrorContains(t, err, "disk events: unexpected syncthing response")
+}
+
+func TestDecodeEventData(t *testing.T) {
+	var events []api.Event
+	require.NoError(t, json.Unmarshal([]byte(testEventsJSON), &events))
+
+	disconnected, err := DecodeEventData[api.DeviceDisconnectedEventData](
+		&events[0])
+	require.NoError(t, err)
+	assert.Equal(t, &api.DeviceDisconnectedEventData{
+		Error: "unexpected EOF",
+		Id:    "XXXXXX2-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX",
+	}, disconnected)
+
+	folderErrors, err := DecodeEventData[api.FolderErrorsEventData](&events[1])
+	require.NoError(t, err)
+	assert.Equal(t, &api.FolderErrorsEventData{
+		Folder: "default",
+		Errors: []api.FileError{{Error: "some error", Path: "/some file path"}},
+	}, folderErrors)
+
+	_, err = DecodeEventData[api.FolderErrorsEventData](&api.Event{
+		Id:   1,
+		Type: EventFolderErrors,
+		Data: json.RawMessage(`"some error"`),
+	})
+	require.ErrorContains(t, err, "decode data of FolderErrors event id=1")
+}
@@ -1,17 +1,120 @@
 package client
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/dsh2dsh/check_syncthing/client/api"
 )
 
-const httpTimeout = 15
+// httpTimeout is default HTTP timeout of every request.
+const httpTimeout = 15 * time.Second
 
 type Option func(c *Client) error
 
+// WithCACert returns an [Option], which verifies server certificate using CA
+// certificates from PEM encoded fname, instead of system CA certificates.
+func WithCACert(fname string) Option {
+	return func(c *Client) error {
+		b, err := os.ReadFile(fname)
+		if err != nil {
+			return fmt.Errorf("read CA certificates: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return fmt.Errorf("no CA certificates found in %q", fname)
+		}
+		c.tls().RootCAs = pool
+		return nil
+	}
+}
+
+// WithClientCert returns an [Option], which presents client certificate from
+// PEM encoded certFile and keyFile to the server.
+func WithClientCert(certFile, keyFile string) Option {
+	return func(c *Client) error {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return fmt.Errorf("load client certificate: %w", err)
+		}
+		c.tls().Certificates = []tls.Certificate{cert}
+		return nil
+	}
+}
+
+// WithFingerprint returns an [Option], which pins server certificate by its
+// SHA-256 fingerprint. The fingerprint is hex encoded and can be separated by
+// colons, like openssl outputs it. A pinned certificate isn't verified against
+// any CA, so it works with self-signed certificates of syncthing GUI.
+func WithFingerprint(fingerprint string) Option {
+	return func(c *Client) error {
+		want, err := parseFingerprint(fingerprint)
+		if err != nil {
+			return err
+		}
+		tlsConfig := c.tls()
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.VerifyConnection = verifyFingerprint(want)
+		return nil
+	}
+}
+
+func parseFingerprint(s string) ([]byte, error) {
+	s = strings.NewReplacer(":", "", " ", "").Replace(s)
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("parse fingerprint %q: %w", s, err)
+	} else if len(b) != sha256.Size {
+		return nil, fmt.Errorf("fingerprint %q isn't SHA-256", s)
+	}
+	return b, nil
+}
+
+func verifyFingerprint(want []byte) func(cs tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return errors.New("server didn't present any certificate")
+		}
+		got := sha256.Sum256(cs.PeerCertificates[0].Raw)
+		if !bytes.Equal(got[:], want) {
+			return fmt.Errorf("server certificate fingerprint %X doesn't match",
+				got)
+		}
+		return nil
+	}
+}
+
+// WithTimeout returns an [Option], which sets HTTP timeout of every request,
+// instead of default 15 seconds. Zero means no timeout, so requests are bounded
+// by their context only.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) error {
+		c.timeout = d
+		return nil
+	}
+}
+
+// WithInsecureSkipVerify returns an [Option], which doesn't verify server
+// certificate at all.
+func WithInsecureSkipVerify() Option {
+	return func(c *Client) error {
+		c.tls().InsecureSkipVerify = true
+		return nil
+	}
+}
+
 func (self *Client) applyOpts(opts []Option) error {
 	for _, fn := range opts {
 		if err := fn(self); err != nil {
@@ -27,11 +130,45 @@ func (self *Client) applyOpts(opts []Option) error {
 	return nil
 }
 
+func (self *Client) tls() *tls.Config {
+	if self.tlsConfig == nil {
+		self.tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	return self.tlsConfig
+}
+
 func (self *Client) withDefaultClient() error {
-	httpClient := &http.Client{Timeout: httpTimeout * time.Second}
+	httpClient := &http.Client{
+		Timeout:   self.timeout,
+		Transport: self.transport(),
+	}
 	err := self.NewClientWithResponses(api.WithHTTPClient(httpClient))
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+func (self *Client) transport() http.RoundTripper {
+	if self.tlsConfig == nil && self.socketPath == "" {
+		return nil
+	}
+
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.TLSClientConfig = self.tlsConfig
+	if self.socketPath != "" {
+		t.DialContext = self.dialUnix
+	}
+	return t
+}
+
+func (self *Client) dialUnix(ctx context.Context, _, _ string) (net.Conn,
+	error,
+) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "unix", self.socketPath)
+	if err != nil {
+		return nil, fmt.Errorf("dial unix socket: %w", err)
+	}
+	return conn, nil
+}
@@ -1,8 +1,24 @@
 package client
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
 	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -39,3 +55,147 @@ func TestClient_applyOpts(t *testing.T) {
 	}))
 	assert.Same(t, c.apiClient, apiClient)
 }
+
+func TestWithCACert(t *testing.T) {
+	srv := newTestTLSServer(t)
+	_, err := New(srv.URL, WithCACert(filepath.Join(t.TempDir(), "ca.pem")))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	fname := writeTestFile(t, "ca.pem", []byte("not a certificate"))
+	_, err = New(srv.URL, WithCACert(fname))
+	require.ErrorContains(t, err, "no CA certificates found in")
+
+	c, err := New(srv.URL)
+	require.NoError(t, err)
+	var certErr *tls.CertificateVerificationError
+	require.ErrorAs(t, c.Health(t.Context()), &certErr)
+
+	fname = writeTestFile(t, "ca.pem", pemEncode("CERTIFICATE",
+		srv.Certificate().Raw))
+	c, err = New(srv.URL, WithCACert(fname))
+	require.NoError(t, err)
+	require.NoError(t, c.Health(t.Context()))
+}
+
+func newTestTLSServer(t *testing.T) *httptest.Server {
+	srv := httptest.NewTLSServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, err := w.Write([]byte(`{ "status": "OK" }`))
+			assert.NoError(t, err)
+		}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func writeTestFile(t *testing.T, name string, b []byte) string {
+	fname := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(fname, b, 0o600))
+	return fname
+}
+
+func pemEncode(typ string, b []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: b})
+}
+
+func TestWithClientCert(t *testing.T) {
+	var clientCerts int
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			clientCerts = len(r.TLS.PeerCertificates)
+			w.Header().Set("Content-Type", "application/json")
+			_, err := w.Write([]byte(`{ "status": "OK" }`))
+			assert.NoError(t, err)
+		}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+
+	_, err := New(srv.URL, WithClientCert(
+		filepath.Join(t.TempDir(), "cert.pem"),
+		filepath.Join(t.TempDir(), "key.pem")))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	c, err := New(srv.URL, WithInsecureSkipVerify())
+	require.NoError(t, err)
+	require.Error(t, c.Health(t.Context()))
+
+	certFile, keyFile := writeTestClientCert(t)
+	c, err = New(srv.URL, WithInsecureSkipVerify(),
+		WithClientCert(certFile, keyFile))
+	require.NoError(t, err)
+	require.NoError(t, c.Health(t.Context()))
+	assert.Equal(t, 1, clientCerts)
+}
+
+func writeTestClientCert(t *testing.T) (certFile, keyFile string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "check_syncthing"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, &tmpl, &tmpl,
+		&key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	certFile = writeTestFile(t, "cert.pem", pemEncode("CERTIFICATE", cert))
+	keyFile = writeTestFile(t, "key.pem", pemEncode("PRIVATE KEY", keyDER))
+	return certFile, keyFile
+}
+
+func TestWithFingerprint(t *testing.T) {
+	srv := newTestTLSServer(t)
+	_, err := New(srv.URL, WithFingerprint("XX"))
+	require.ErrorContains(t, err, "parse fingerprint")
+
+	_, err = New(srv.URL, WithFingerprint("AB:CD"))
+	require.ErrorContains(t, err, "isn't SHA-256")
+
+	c, err := New(srv.URL, WithFingerprint(strings.Repeat("00", sha256.Size)))
+	require.NoError(t, err)
+	require.ErrorContains(t, c.Health(t.Context()), "doesn't match")
+
+	sum := sha256.Sum256(srv.Certificate().Raw)
+	fingerprint := make([]string, len(sum))
+	for i, b := range sum {
+		fingerprint[i] = hex.EncodeToString([]byte{b})
+	}
+
+	c, err = New(srv.URL, WithFingerprint(strings.Join(fingerprint, ":")))
+	require.NoError(t, err)
+	require.NoError(t, c.Health(t.Context()))
+
+	c, err = New(srv.URL, WithFingerprint(hex.EncodeToString(sum[:])))
+	require.NoError(t, err)
+	require.NoError(t, c.Health(t.Context()))
+}
+
+func TestWithInsecureSkipVerify(t *testing.T) {
+	srv := newTestTLSServer(t)
+	c, err := New(srv.URL, WithInsecureSkipVerify())
+	require.NoError(t, err)
+	require.NoError(t, c.Health(t.Context()))
+}
+
+func TestWithTimeout(t *testing.T) {
+	c, err := New("http://127.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, httpTimeout, c.timeout)
+
+	c, err = New("http://127.0.0.1", WithTimeout(0))
+	require.NoError(t, err)
+	assert.Zero(t, c.timeout)
+
+	c, err = New("http://127.0.0.1", WithTimeout(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, c.timeout)
+}
@@ -0,0 +1,144 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/dsh2dsh/check_syncthing/client/api"
+)
+
+const maxRetryDelay = 10 * time.Second
+
+// WithRetry returns an [Option], which retries idempotent requests up to
+// attempts times, after transient network errors and HTTP statuses 429, 502,
+// 503 and 504. Delay between retries starts from delay and doubles after every
+// retry, with random jitter. Timeouts aren't retried, because every of them
+// already took the whole HTTP timeout, and it doesn't retry, if the delay
+// exceeds deadline of the request.
+func WithRetry(attempts int, delay time.Duration) Option {
+	return func(c *Client) error {
+		c.retryAttempts, c.retryDelay = attempts, delay
+		return nil
+	}
+}
+
+// Retries returns number of retried requests.
+func (self *Client) Retries() int {
+	return int(self.retries.Load())
+}
+
+func (self *Client) withRetry(c *api.Client) error {
+	doer := c.Client
+	if doer == nil {
+		doer = &http.Client{}
+	}
+	c.Client = &retryDoer{client: self, doer: doer}
+	return nil
+}
+
+// --------------------------------------------------
+
+type retryDoer struct {
+	client *Client
+	doer   api.HttpRequestDoer
+}
+
+func (self *retryDoer) Do(req *http.Request) (*http.Response, error) {
+	resp, err := self.doer.Do(req)
+	for attempt := range self.client.retryAttempts {
+		if !retryable(req, resp, err) || !self.wait(req.Context(), attempt) {
+			break
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+		self.client.retries.Add(1)
+		resp, err = self.doer.Do(req)
+	}
+	return resp, err
+}
+
+func (self *retryDoer) wait(ctx context.Context, attempt int) bool {
+	d := retryDelay(self.client.retryDelay, attempt)
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= d {
+		return false
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+	}
+	return true
+}
+
+// retryDelay returns exponential delay of given attempt, with jitter up to a
+// half of it. It doubles delay while it's below [maxRetryDelay], so it never
+// overflows.
+func retryDelay(delay time.Duration, attempt int) time.Duration {
+	if delay <= 0 {
+		return 0
+	}
+
+	d := delay
+	for i := 0; i < attempt && d < maxRetryDelay; i++ {
+		d <<= 1
+	}
+	d = min(d, maxRetryDelay)
+	return d/2 + rand.N(d/2+1)
+}
+
+// noRetryKey is a key of context value, which disables retries of requests.
+type noRetryKey struct{}
+
+// withoutRetry returns ctx, which disables retries of requests with it.
+func withoutRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noRetryKey{}, true)
+}
+
+func retryable(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Value(noRetryKey{}) != nil {
+		return false
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+	default:
+		return false
+	}
+
+	if err != nil {
+		return req.Context().Err() == nil && retryableError(err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func retryableError(err error) bool {
+	var netErr net.Error
+	if errors.Is(err, context.Canceled) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		(errors.As(err, &netErr) && netErr.Timeout()) {
+		return false
+	}
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNABORTED) ||
+		errors.Is(err, syscall.EPIPE)
+}
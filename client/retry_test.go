@@ -0,0 +1,232 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dsh2dsh/check_syncthing/client/api"
+)
+
+type testDoerFunc func(req *http.Request) (*http.Response, error)
+
+func (self testDoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return self(req)
+}
+
+func newTestRetryClient(t *testing.T, attempts int, results ...any,
+) (*Client, *int) {
+	var calls int
+	doer := testDoerFunc(func(req *http.Request) (*http.Response, error) {
+		require.Less(t, calls, len(results), "unexpected request")
+		result := results[calls]
+		calls++
+		switch v := result.(type) {
+		case int:
+			r := httptest.NewRecorder()
+			r.Header().Set("Content-Type", "application/json")
+			r.WriteHeader(v)
+			_, err := r.WriteString(`{ "status": "OK" }`)
+			require.NoError(t, err)
+			return r.Result(), nil
+		case error:
+			return nil, v
+		}
+		return nil, fmt.Errorf("unexpected result %#v", result)
+	})
+
+	c, err := New("/", WithRetry(attempts, 0), func(c *Client) error {
+		return c.NewClientWithResponses(api.WithHTTPClient(doer))
+	})
+	require.NoError(t, err)
+	return c, &calls
+}
+
+func TestWithRetry(t *testing.T) {
+	tests := []struct {
+		name     string
+		attempts int
+		results  []any
+		wantErr  bool
+		calls    int
+		retries  int
+	}{
+		{
+			name:     "without retries",
+			attempts: 0,
+			results:  []any{http.StatusServiceUnavailable},
+			wantErr:  true,
+			calls:    1,
+		},
+		{
+			name:     "no errors",
+			attempts: 2,
+			results:  []any{http.StatusOK},
+			calls:    1,
+		},
+		{
+			name:     "retryable status",
+			attempts: 2,
+			results: []any{
+				http.StatusServiceUnavailable,
+				http.StatusBadGateway,
+				http.StatusOK,
+			},
+			calls:   3,
+			retries: 2,
+		},
+		{
+			name:     "connection reset",
+			attempts: 2,
+			results:  []any{syscall.ECONNRESET, http.StatusOK},
+			calls:    2,
+			retries:  1,
+		},
+		{
+			name:     "out of attempts",
+			attempts: 1,
+			results: []any{
+				http.StatusTooManyRequests,
+				http.StatusGatewayTimeout,
+			},
+			wantErr: true,
+			calls:   2,
+			retries: 1,
+		},
+		{
+			name:     "not retryable status",
+			attempts: 2,
+			results:  []any{http.StatusInternalServerError},
+			wantErr:  true,
+			calls:    1,
+		},
+		{
+			name:     "not retryable error",
+			attempts: 2,
+			results:  []any{errors.New("test error")},
+			wantErr:  true,
+			calls:    1,
+		},
+		{
+			name:     "deadline",
+			attempts: 2,
+			results:  []any{context.DeadlineExceeded},
+			wantErr:  true,
+			calls:    1,
+		},
+		{
+			name:     "network timeout",
+			attempts: 2,
+			results:  []any{&net.DNSError{Err: "test error", IsTimeout: true}},
+			wantErr:  true,
+			calls:    1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, calls := newTestRetryClient(t, tt.attempts, tt.results...)
+			err := c.Health(t.Context())
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.calls, *calls)
+			assert.Equal(t, tt.retries, c.Retries())
+		})
+	}
+}
+
+func TestWithRetry_canceled(t *testing.T) {
+	c, calls := newTestRetryClient(t, 2, syscall.ECONNREFUSED)
+	c.retryDelay = time.Minute
+
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, c.Health(ctx), syscall.ECONNREFUSED)
+	assert.Equal(t, 1, *calls)
+	assert.Zero(t, c.Retries())
+}
+
+func TestWithRetry_deadline(t *testing.T) {
+	c, calls := newTestRetryClient(t, 2, syscall.ECONNREFUSED)
+	c.retryDelay = time.Minute
+
+	ctx, cancel := context.WithTimeout(t.Context(), time.Second)
+	defer cancel()
+	require.ErrorIs(t, c.Health(ctx), syscall.ECONNREFUSED)
+	assert.NoError(t, ctx.Err(), "no wait for deadline")
+	assert.Equal(t, 1, *calls)
+	assert.Zero(t, c.Retries())
+}
+
+func TestWithRetry_systemUpgrade(t *testing.T) {
+	c, calls := newTestRetryClient(t, 2, http.StatusServiceUnavailable)
+	_, err := c.SystemUpgrade(t.Context())
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+	assert.Equal(t, 1, *calls)
+	assert.Zero(t, c.Retries())
+}
+
+func TestWithRetry_hungServer(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			<-r.Context().Done()
+		}))
+	t.Cleanup(srv.Close)
+
+	httpClient := &http.Client{Timeout: 50 * time.Millisecond}
+	c, err := New(srv.URL, WithRetry(2, 0), func(c *Client) error {
+		return c.NewClientWithResponses(api.WithHTTPClient(httpClient))
+	})
+	require.NoError(t, err)
+
+	require.Error(t, c.Health(t.Context()))
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Zero(t, c.Retries())
+}
+
+func TestRetryable(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	assert.False(t, retryable(req,
+		&http.Response{StatusCode: http.StatusServiceUnavailable}, nil))
+	assert.False(t, retryable(req, nil, syscall.ECONNRESET))
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	assert.True(t, retryable(req,
+		&http.Response{StatusCode: http.StatusServiceUnavailable}, nil))
+	assert.True(t, retryable(req, nil, syscall.ECONNRESET))
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Zero(t, retryDelay(0, 3))
+
+	for attempt, want := range []time.Duration{
+		time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second,
+		maxRetryDelay, maxRetryDelay,
+	} {
+		d := retryDelay(time.Second, attempt)
+		assert.GreaterOrEqual(t, d, want/2)
+		assert.LessOrEqual(t, d, want)
+	}
+	for _, attempt := range []int{63, 64, 100, 1000} {
+		d := retryDelay(time.Second, attempt)
+		assert.GreaterOrEqual(t, d, maxRetryDelay/2, "attempt %v", attempt)
+		assert.LessOrEqual(t, d, maxRetryDelay, "attempt %v", attempt)
+	}
+}
@@ -0,0 +1,115 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dsh2dsh/check_syncthing/client/api"
+)
+
+// LoadSyncState loads [SyncState] from state file fname. It returns empty
+// state, if fname doesn't exist yet.
+func LoadSyncState(fname string) (*SyncState, error) {
+	s := &SyncState{fname: fname}
+	b, err := os.ReadFile(fname)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("read sync state: %w", err)
+	}
+
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, fmt.Errorf("parse sync state %q: %w", fname, err)
+	}
+	return s, nil
+}
+
+// SyncState remembers out of sync folder and device pairs between plugin
+// invocations, so it's possible to tell how long they are out of sync and
+// since when there is no progress.
+type SyncState struct {
+	// Folders maps folder ID to device ID to out of sync pair.
+	Folders map[string]map[string]*SyncPair `json:"folders"`
+
+	fname string
+	seen  map[*SyncPair]struct{}
+}
+
+// SyncPair is a folder and device pair, which is out of sync.
+type SyncPair struct {
+	// OutOfSyncSince is when the pair was seen out of sync first time.
+	OutOfSyncSince time.Time `json:"outOfSyncSince"`
+
+	// ProgressTime is when completion or sequence of the pair changed last
+	// time.
+	ProgressTime time.Time `json:"progressTime"`
+
+	Completion float64 `json:"completion"`
+	Sequence   int64   `json:"sequence"`
+}
+
+// Update updates state of folder on device with its completion at now and
+// returns out of sync pair, or nil if the folder is in sync on the device.
+func (self *SyncState) Update(folder, device string,
+	comp *api.FolderCompletion, now time.Time,
+) *SyncPair {
+	devices := self.Folders[folder]
+	if comp.Completion >= 100 {
+		delete(devices, device)
+		return nil
+	}
+
+	pair, ok := devices[device]
+	switch {
+	case !ok:
+		pair = &SyncPair{OutOfSyncSince: now, ProgressTime: now}
+		if devices == nil {
+			devices = make(map[string]*SyncPair)
+			if self.Folders == nil {
+				self.Folders = make(map[string]map[string]*SyncPair)
+			}
+			self.Folders[folder] = devices
+		}
+		devices[device] = pair
+	case pair.Completion != comp.Completion || pair.Sequence != comp.Sequence:
+		pair.ProgressTime = now
+	}
+	pair.Completion, pair.Sequence = comp.Completion, comp.Sequence
+	self.markSeen(pair)
+	return pair
+}
+
+// Keep keeps state of folder on device unchanged, so [SyncState.Save] doesn't
+// remove it. It's for pairs, which completion wasn't fetched, like after
+// timeout.
+func (self *SyncState) Keep(folder, device string) {
+	if pair, ok := self.Folders[folder][device]; ok {
+		self.markSeen(pair)
+	}
+}
+
+func (self *SyncState) markSeen(pair *SyncPair) {
+	if self.seen == nil {
+		self.seen = make(map[*SyncPair]struct{})
+	}
+	self.seen[pair] = struct{}{}
+}
+
+// Save removes pairs, which weren't updated or kept since loading, and
+// atomically writes the state to its state file.
+func (self *SyncState) Save() error {
+	for folder, devices := range self.Folders {
+		for device, pair := range devices {
+			if _, ok := self.seen[pair]; !ok {
+				delete(devices, device)
+			}
+		}
+		if len(devices) == 0 {
+			delete(self.Folders, folder)
+		}
+	}
+	return saveState(self.fname, "sync state", self)
+}
@@ -0,0 +1,86 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dsh2dsh/check_syncthing/client/api"
+)
+
+func TestSyncState(t *testing.T) {
+	fname := filepath.Join(t.TempDir(), "sync.json")
+	s, err := LoadSyncState(fname)
+	require.NoError(t, err)
+	assert.Empty(t, s.Folders)
+
+	now := time.Date(2024, 3, 28, 20, 15, 11, 0, time.UTC)
+	assert.Nil(t, s.Update("default", "dev1",
+		&api.FolderCompletion{Completion: 100}, now))
+
+	pair := s.Update("default", "dev1",
+		&api.FolderCompletion{Completion: 97, Sequence: 10}, now)
+	require.NotNil(t, pair)
+	assert.Equal(t, &SyncPair{
+		OutOfSyncSince: now, ProgressTime: now, Completion: 97, Sequence: 10,
+	}, pair)
+	require.NotNil(t, s.Update("photos", "dev1",
+		&api.FolderCompletion{Completion: 50}, now))
+	require.NoError(t, s.Save())
+
+	entries, err := os.ReadDir(filepath.Dir(fname))
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "no temporary files")
+
+	s, err = LoadSyncState(fname)
+	require.NoError(t, err)
+	later := now.Add(time.Hour)
+	pair = s.Update("default", "dev1",
+		&api.FolderCompletion{Completion: 97, Sequence: 10}, later)
+	assert.Equal(t, now, pair.OutOfSyncSince)
+	assert.Equal(t, now, pair.ProgressTime, "no progress")
+
+	pair = s.Update("default", "dev1",
+		&api.FolderCompletion{Completion: 97, Sequence: 11}, later)
+	assert.Equal(t, now, pair.OutOfSyncSince)
+	assert.Equal(t, later, pair.ProgressTime, "sequence changed")
+	require.NoError(t, s.Save())
+
+	s, err = LoadSyncState(fname)
+	require.NoError(t, err)
+	assert.Len(t, s.Folders, 1, "photos wasn't updated")
+	s.Keep("default", "dev1")
+	s.Keep("photos", "dev1")
+	require.NoError(t, s.Save())
+
+	s, err = LoadSyncState(fname)
+	require.NoError(t, err)
+	assert.Equal(t, &SyncPair{
+		OutOfSyncSince: now, ProgressTime: later, Completion: 97, Sequence: 11,
+	}, s.Folders["default"]["dev1"], "kept unchanged")
+	assert.Nil(t, s.Update("default", "dev1",
+		&api.FolderCompletion{Completion: 100}, later))
+	require.NoError(t, s.Save())
+
+	s, err = LoadSyncState(fname)
+	require.NoError(t, err)
+	assert.Empty(t, s.Folders)
+}
+
+func TestLoadSyncState_errors(t *testing.T) {
+	fname := filepath.Join(t.TempDir(), "sync.json")
+	require.NoError(t, os.WriteFile(fname, []byte("{"), 0o600))
+	_, err := LoadSyncState(fname)
+	require.ErrorContains(t, err, "parse sync state")
+
+	_, err = LoadSyncState(t.TempDir())
+	require.ErrorContains(t, err, "read sync state")
+
+	s, err := LoadSyncState(filepath.Join(t.TempDir(), "nodir", "sync.json"))
+	require.NoError(t, err)
+	require.ErrorContains(t, s.Save(), "create sync state")
+}
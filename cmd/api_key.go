@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const keyCommandWaitDelay = time.Second
+
+var (
+	keyFile, keyCommand string
+
+	// apiKeySource describes where API key came from, for debug output. Never
+	// log API key itself.
+	apiKeySource string
+)
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&keyFile, "key-file", "",
+		"read syncthing REST API key from file")
+	rootCmd.PersistentFlags().StringVar(&keyCommand, "key-command", "",
+		"read syncthing REST API key from stdout of shell command")
+}
+
+// loadAPIKey reads API key from --key-file or --key-command, if it wasn't
+// configured by --key flag or SYNCTHING_API_KEY environment variable.
+func loadAPIKey(ctx context.Context) (err error) {
+	switch {
+	case apiKey != "":
+		return nil
+	case keyFile != "":
+		apiKey, err = readKeyFile(keyFile)
+		apiKeySource = "file " + keyFile
+	case keyCommand != "":
+		apiKey, err = runKeyCommand(ctx, keyCommand)
+		apiKeySource = "command " + keyCommand
+	}
+	return err
+}
+
+func readKeyFile(fname string) (string, error) {
+	f, err := os.Open(fname)
+	if err != nil {
+		return "", fmt.Errorf("open API key file: %w", err)
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return "", fmt.Errorf("stat API key file: %w", err)
+	} else if fi.Mode().Perm()&0o004 != 0 {
+		return "", fmt.Errorf(
+			"API key file %q is world-readable (%v), restrict its permissions",
+			fname, fi.Mode().Perm())
+	}
+
+	var b bytes.Buffer
+	if _, err := b.ReadFrom(f); err != nil {
+		return "", fmt.Errorf("read API key file %q: %w", fname, err)
+	}
+
+	key := strings.TrimSpace(b.String())
+	if key == "" {
+		return "", fmt.Errorf("empty API key file %q", fname)
+	}
+	return key, nil
+}
+
+func runKeyCommand(ctx context.Context, command string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stderr = &stderr
+	// Don't wait for children of killed shell, which keep its output open.
+	cmd.WaitDelay = keyCommandWaitDelay
+
+	stdout, err := cmd.Output()
+	if ctx.Err() != nil {
+		return "", fmt.Errorf("run API key command: %w", ctx.Err())
+	} else if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			err = fmt.Errorf("%w: %v", err, msg)
+		}
+		return "", fmt.Errorf("run API key command: %w", err)
+	}
+
+	key, _, _ := strings.Cut(string(stdout), "\n")
+	if key = strings.TrimSpace(key); key == "" {
+		return "", errors.New("API key command printed nothing")
+	}
+	return key, nil
+}
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func saveTestKeyGlobals(t *testing.T) {
+	origKey, origKeyFile, origKeyCommand := apiKey, keyFile, keyCommand
+	origSource := apiKeySource
+	t.Cleanup(func() {
+		apiKey, keyFile, keyCommand = origKey, origKeyFile, origKeyCommand
+		apiKeySource = origSource
+	})
+	apiKey, keyFile, keyCommand, apiKeySource = "", "", "", ""
+}
+
+func TestLoadAPIKey(t *testing.T) {
+	saveTestKeyGlobals(t)
+
+	require.NoError(t, loadAPIKey(t.Context()))
+	assert.Empty(t, apiKey)
+
+	keyFile = writeTestKeyFile(t, "foobar\n", 0o600)
+	keyCommand = "echo foobaz"
+	require.NoError(t, loadAPIKey(t.Context()))
+	assert.Equal(t, "foobar", apiKey)
+	assert.Equal(t, "file "+keyFile, apiKeySource)
+
+	require.NoError(t, loadAPIKey(t.Context()))
+	assert.Equal(t, "foobar", apiKey)
+
+	apiKey, keyFile = "", ""
+	require.NoError(t, loadAPIKey(t.Context()))
+	assert.Equal(t, "foobaz", apiKey)
+	assert.Equal(t, "command echo foobaz", apiKeySource)
+
+	apiKey, keyCommand = "", "false"
+	require.ErrorContains(t, loadAPIKey(t.Context()), "run API key command")
+}
+
+func writeTestKeyFile(t *testing.T, key string, perm os.FileMode) string {
+	fname := filepath.Join(t.TempDir(), "api.key")
+	require.NoError(t, os.WriteFile(fname, []byte(key), perm))
+	require.NoError(t, os.Chmod(fname, perm))
+	return fname
+}
+
+func TestReadKeyFile(t *testing.T) {
+	_, err := readKeyFile(filepath.Join(t.TempDir(), "api.key"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	_, err = readKeyFile(writeTestKeyFile(t, "foobar", 0o644))
+	require.ErrorContains(t, err, "world-readable")
+
+	_, err = readKeyFile(writeTestKeyFile(t, " \n", 0o600))
+	require.ErrorContains(t, err, "empty API key file")
+
+	key, err := readKeyFile(writeTestKeyFile(t, " foobar\n", 0o640))
+	require.NoError(t, err)
+	assert.Equal(t, "foobar", key)
+}
+
+func TestRunKeyCommand(t *testing.T) {
+	key, err := runKeyCommand(t.Context(), "printf 'foobar\nsecond line\n'")
+	require.NoError(t, err)
+	assert.Equal(t, "foobar", key)
+
+	_, err = runKeyCommand(t.Context(), "true")
+	require.ErrorContains(t, err, "printed nothing")
+
+	_, err = runKeyCommand(t.Context(), "echo some error >&2; exit 1")
+	require.ErrorContains(t, err, "some error")
+
+	ctx := withTestTimeout(t, 50*time.Millisecond)
+	start := time.Now()
+	_, err = runKeyCommand(ctx, "sleep 10; echo foobar")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestPersistentPreRunE_debug(t *testing.T) {
+	saveTestKeyGlobals(t)
+	origBaseURL, origDebug, origLog := baseURL, debug, debugLog.Writer()
+	t.Cleanup(func() {
+		baseURL, debug = origBaseURL, origDebug
+		debugLog.SetOutput(origLog)
+	})
+
+	var b bytes.Buffer
+	debugLog.SetOutput(&b)
+	debug = true
+	baseURL = "http://127.0.0.1"
+	keyCommand = "echo foobar | tr o x"
+	cmd := cobra.Command{}
+	cmd.SetContext(t.Context())
+	require.NoError(t, persistentPreRunE(&cmd, []string{}))
+	assert.Equal(t, "fxxbar", apiKey)
+	assert.Equal(t, "debug: API key from command echo foobar | tr o x\n",
+		b.String())
+}
+
+func TestPersistentPreRunE_timeout(t *testing.T) {
+	saveTestKeyGlobals(t)
+	origBaseURL, origTimeout := baseURL, checkTimeout
+	t.Cleanup(func() { baseURL, checkTimeout = origBaseURL, origTimeout })
+
+	baseURL = "http://127.0.0.1"
+	keyCommand = "sleep 10; echo foobar"
+	checkTimeout = 50 * time.Millisecond
+	cmd := cobra.Command{}
+	cmd.SetContext(t.Context())
+	require.ErrorIs(t, persistentPreRunE(&cmd, []string{}),
+		context.DeadlineExceeded)
+}
+
+func TestLoadEnvs_keySource(t *testing.T) {
+	saveTestKeyGlobals(t)
+	t.Setenv("SYNCTHING_API_KEY_FILE", "api.key")
+	t.Setenv("SYNCTHING_API_KEY_COMMAND", "echo foobar")
+	require.NoError(t, loadEnvs())
+	assert.Equal(t, "api.key", keyFile)
+	assert.Equal(t, "echo foobar", keyCommand)
+
+	keyFile, keyCommand = "", ""
+	t.Setenv("SYNCTHING_API_KEY", "foobaz")
+	require.NoError(t, loadEnvs())
+	assert.Equal(t, "SYNCTHING_API_KEY", apiKeySource)
+
+	require.NoError(t, loadEnvs())
+	assert.Equal(t, "--key flag", apiKeySource)
+}
+
+func TestLoadEnvs_keyFlagsBeforeEnv(t *testing.T) {
+	saveTestKeyGlobals(t)
+	t.Setenv("SYNCTHING_API_KEY", "foobaz")
+
+	keyFile = writeTestKeyFile(t, "foobar\n", 0o600)
+	require.NoError(t, loadEnvs())
+	assert.Empty(t, apiKey)
+	require.NoError(t, loadAPIKey(t.Context()))
+	assert.Equal(t, "foobar", apiKey)
+	assert.Equal(t, "file "+keyFile, apiKeySource)
+
+	apiKey, apiKeySource, keyFile, keyCommand = "", "", "", "echo foobar"
+	require.NoError(t, loadEnvs())
+	assert.Empty(t, apiKey)
+	require.NoError(t, loadAPIKey(t.Context()))
+	assert.Equal(t, "foobar", apiKey)
+	assert.Equal(t, "command echo foobar", apiKeySource)
+
+	apiKey, apiKeySource, keyCommand = "", "", ""
+	require.NoError(t, loadEnvs())
+	assert.Equal(t, "foobaz", apiKey)
+	assert.Equal(t, "SYNCTHING_API_KEY", apiKeySource)
+}
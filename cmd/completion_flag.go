@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dsh2dsh/go-monitoringplugin/v2"
+)
+
+// completionThresholds are warning and critical thresholds of completion
+// percentage. Completion below a threshold has its status. Zero threshold is
+// disabled.
+type completionThresholds struct {
+	warn, crit float64
+}
+
+func (self completionThresholds) Status(completion float64) int {
+	switch {
+	case completion < self.crit:
+		return monitoringplugin.CRITICAL
+	case completion < self.warn:
+		return monitoringplugin.WARNING
+	}
+	return monitoringplugin.OK
+}
+
+func (self completionThresholds) String() string {
+	s := strconv.FormatFloat(self.warn, 'f', -1, 64)
+	if self.crit == 0 {
+		return s
+	}
+	return s + ":" + strconv.FormatFloat(self.crit, 'f', -1, 64)
+}
+
+// --------------------------------------------------
+
+// newOverridesValue returns flag value, which adds completion thresholds to p,
+// given as KEY=WARN[:CRIT], like nas=90:50. Omitted CRIT is disabled.
+func newOverridesValue(p *map[string]completionThresholds) *overridesValue {
+	if *p == nil {
+		*p = make(map[string]completionThresholds)
+	}
+	return (*overridesValue)(p)
+}
+
+type overridesValue map[string]completionThresholds
+
+func (self *overridesValue) String() string {
+	overrides := make([]string, 0, len(*self))
+	for _, key := range slices.Sorted(maps.Keys(*self)) {
+		overrides = append(overrides, key+"="+(*self)[key].String())
+	}
+	return strings.Join(overrides, ",")
+}
+
+func (self *overridesValue) Set(s string) error {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("override %q not KEY=WARN[:CRIT]", s)
+	}
+
+	thresholds, err := parseCompletionThresholds(value)
+	if err != nil {
+		return fmt.Errorf("override %q: %w", s, err)
+	}
+	(*self)[key] = thresholds
+	return nil
+}
+
+func (self *overridesValue) Type() string { return "override" }
+
+func parseCompletionThresholds(s string) (t completionThresholds, err error) {
+	warn, crit, hasCrit := strings.Cut(s, ":")
+	if t.warn, err = parseCompletion(warn); err != nil {
+		return t, err
+	} else if hasCrit {
+		t.crit, err = parseCompletion(crit)
+	}
+	return t, err
+}
+
+func parseCompletion(s string) (float64, error) {
+	pct, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse completion %q: %w", s, err)
+	} else if err := validCompletion(pct); err != nil {
+		return 0, err
+	}
+	return pct, nil
+}
+
+func validCompletion(pct float64) error {
+	if pct < 0 || pct > 100 {
+		return fmt.Errorf("completion %q out of range 0..100",
+			strconv.FormatFloat(pct, 'f', -1, 64))
+	}
+	return nil
+}
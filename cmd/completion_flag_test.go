@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/dsh2dsh/go-monitoringplugin/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompletionThresholds_Status(t *testing.T) {
+	thresholds := completionThresholds{warn: 99, crit: 50}
+	assert.Equal(t, monitoringplugin.OK, thresholds.Status(100))
+	assert.Equal(t, monitoringplugin.OK, thresholds.Status(99))
+	assert.Equal(t, monitoringplugin.WARNING, thresholds.Status(98.9))
+	assert.Equal(t, monitoringplugin.CRITICAL, thresholds.Status(40))
+
+	assert.Equal(t, monitoringplugin.OK, completionThresholds{}.Status(0))
+}
+
+func TestOverridesValue(t *testing.T) {
+	var overrides map[string]completionThresholds
+	value := newOverridesValue(&overrides)
+	require.NotNil(t, overrides)
+	assert.Empty(t, value.String())
+	assert.Equal(t, "override", value.Type())
+
+	require.NoError(t, value.Set("nas=90:50"))
+	require.NoError(t, value.Set("laptop=99.5"))
+	assert.Equal(t, map[string]completionThresholds{
+		"nas":    {warn: 90, crit: 50},
+		"laptop": {warn: 99.5},
+	}, overrides)
+	assert.Equal(t, "laptop=99.5,nas=90:50", value.String())
+
+	require.Error(t, value.Set("nas"))
+	require.Error(t, value.Set("=90"))
+	require.Error(t, value.Set("nas=foo"))
+	require.Error(t, value.Set("nas=90:101"))
+	assert.Len(t, overrides, 2)
+}
+
+func TestParseCompletionThresholds(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected completionThresholds
+		wantErr  bool
+	}{
+		{s: "100", expected: completionThresholds{warn: 100}},
+		{s: "90:50", expected: completionThresholds{warn: 90, crit: 50}},
+		{s: "0:50", expected: completionThresholds{crit: 50}},
+		{s: "", wantErr: true},
+		{s: "90:", wantErr: true},
+		{s: "-1", wantErr: true},
+		{s: "90:foo", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			thresholds, err := parseCompletionThresholds(tt.s)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, thresholds)
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/pem"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const httpsCertName = "https-cert.pem"
+
+var configXML string
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&configXML, "config-xml", "",
+		"syncthing config.xml for server URL and API key")
+}
+
+// loadConfigXML fills server URL, API key and TLS mode from syncthing
+// config.xml, if they weren't configured by flags or environment variables.
+// Without --config-xml it looks for config.xml in default syncthing locations
+// and silently does nothing if there is no one.
+func loadConfigXML() error {
+	if baseURL != "" && apiKey != "" {
+		return nil
+	}
+
+	fname := configXML
+	if fname == "" {
+		if fname = lookupConfigXML(); fname == "" {
+			return nil
+		}
+	}
+
+	cfg, err := readConfigXML(fname)
+	if err != nil {
+		return err
+	}
+
+	// API key of local syncthing must not be sent to somewhere else.
+	if apiKey == "" && (baseURL == "" || localURL(baseURL)) {
+		apiKey, apiKeySource = cfg.GUI.APIKey, fname
+	}
+	if baseURL == "" {
+		if baseURL, err = cfg.GUI.URL(); err != nil {
+			return fmt.Errorf("config %q: %w", fname, err)
+		}
+		if cfg.GUI.UseTLS() {
+			return pinHTTPSCert(filepath.Join(filepath.Dir(fname), httpsCertName))
+		}
+	}
+	return nil
+}
+
+// localURL returns true, if rawURL points to unix socket or loopback address.
+func localURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+
+	switch u.Scheme {
+	case "unix", "unixs":
+		return true
+	}
+
+	host := u.Hostname()
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func lookupConfigXML() string {
+	for _, fname := range defaultConfigXML() {
+		if _, err := os.Stat(fname); err == nil {
+			return fname
+		}
+	}
+	return ""
+}
+
+func defaultConfigXML() []string {
+	dirs := make([]string, 0, 2)
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		dirs = append(dirs, dir)
+	} else if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, ".local", "state"))
+	}
+
+	if dir, err := os.UserConfigDir(); err == nil {
+		dirs = append(dirs, dir)
+	}
+
+	paths := make([]string, len(dirs))
+	for i, dir := range dirs {
+		paths[i] = filepath.Join(dir, "syncthing", "config.xml")
+	}
+	return paths
+}
+
+func readConfigXML(fname string) (*syncthingConfig, error) {
+	b, err := os.ReadFile(fname)
+	if err != nil {
+		return nil, fmt.Errorf("read syncthing config: %w", err)
+	}
+
+	cfg := new(syncthingConfig)
+	if err := xml.Unmarshal(b, cfg); err != nil {
+		return nil, fmt.Errorf("parse syncthing config %q: %w", fname, err)
+	}
+	return cfg, nil
+}
+
+// pinHTTPSCert pins self-signed certificate of syncthing GUI, unless TLS was
+// configured by flags or environment variables.
+func pinHTTPSCert(fname string) error {
+	if caCert != "" || fingerprint != "" || insecure {
+		return nil
+	}
+
+	b, err := os.ReadFile(fname)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("read syncthing GUI certificate: %w", err)
+	}
+
+	block, _ := pem.Decode(b)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return fmt.Errorf("no certificate found in %q", fname)
+	}
+	sum := sha256.Sum256(block.Bytes)
+	fingerprint = hex.EncodeToString(sum[:])
+	return nil
+}
+
+// --------------------------------------------------
+
+type syncthingConfig struct {
+	GUI guiConfig `xml:"gui"`
+}
+
+type guiConfig struct {
+	TLS     bool   `xml:"tls,attr"`
+	Address string `xml:"address"`
+	APIKey  string `xml:"apikey"`
+}
+
+func (self *guiConfig) UseTLS() bool {
+	return self.TLS || strings.HasPrefix(self.Address, "https://") ||
+		strings.HasPrefix(self.Address, "unixs://")
+}
+
+// URL returns URL of syncthing GUI, like syncthing does it. Unspecified
+// listen address is replaced by loopback address.
+func (self *guiConfig) URL() (string, error) {
+	addr := self.Address
+	if addr == "" {
+		return "", errors.New("empty GUI address")
+	} else if strings.HasPrefix(addr, "/") {
+		if self.UseTLS() {
+			return "unixs://" + addr, nil
+		}
+		return "unix://" + addr, nil
+	} else if strings.Contains(addr, "://") {
+		u, err := url.Parse(addr)
+		if err != nil {
+			return "", fmt.Errorf("parse GUI address %q: %w", addr, err)
+		} else if u.Host != "" {
+			u.Host = loopbackHost(u.Host)
+		}
+		return u.String(), nil
+	}
+
+	u := url.URL{Scheme: "http", Host: loopbackHost(addr), Path: "/"}
+	if self.UseTLS() {
+		u.Scheme = "https"
+	}
+	return u.String(), nil
+}
+
+func loopbackHost(hostport string) string {
+	host, port, err := net.SplitHostPort(hostport)
+	if err != nil {
+		return hostport
+	}
+
+	switch host {
+	case "", "0.0.0.0":
+		host = "127.0.0.1"
+	case "::":
+		host = "::1"
+	}
+	return net.JoinHostPort(host, port)
+}
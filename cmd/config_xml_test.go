@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfigXML = `<configuration version="37">
+    <gui enabled="true" tls="%v" debugging="false">
+        <address>%v</address>
+        <apikey>XXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXX</apikey>
+        <theme>default</theme>
+    </gui>
+</configuration>`
+
+func writeTestConfigXML(t *testing.T, dir string, tls bool, addr string,
+) string {
+	require.NoError(t, os.MkdirAll(dir, 0o700))
+	fname := filepath.Join(dir, "config.xml")
+	b := []byte(fmt.Sprintf(testConfigXML, tls, addr))
+	require.NoError(t, os.WriteFile(fname, b, 0o600))
+	return fname
+}
+
+func saveTestGlobals(t *testing.T) {
+	origKey, origBaseURL, origConfigXML := apiKey, baseURL, configXML
+	origCACert, origFingerprint, origInsecure := caCert, fingerprint, insecure
+	t.Cleanup(func() {
+		apiKey, baseURL, configXML = origKey, origBaseURL, origConfigXML
+		caCert, fingerprint, insecure = origCACert, origFingerprint, origInsecure
+	})
+	apiKey, baseURL, configXML = "", "", ""
+	caCert, fingerprint, insecure = "", "", false
+}
+
+func TestLoadConfigXML(t *testing.T) {
+	saveTestGlobals(t)
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	require.NoError(t, loadConfigXML())
+	assert.Empty(t, apiKey)
+	assert.Empty(t, baseURL)
+
+	configXML = filepath.Join(t.TempDir(), "config.xml")
+	require.ErrorIs(t, loadConfigXML(), os.ErrNotExist)
+
+	configXML = writeTestConfigXML(t, t.TempDir(), false, "0.0.0.0:8384")
+	require.NoError(t, loadConfigXML())
+	assert.Equal(t, "XXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXX", apiKey)
+	assert.Equal(t, "http://127.0.0.1:8384/", baseURL)
+
+	apiKey, baseURL = "foobar", ""
+	require.NoError(t, loadConfigXML())
+	assert.Equal(t, "foobar", apiKey)
+	assert.Equal(t, "http://127.0.0.1:8384/", baseURL)
+
+	apiKey, baseURL = "", "http://localhost:8384"
+	require.NoError(t, loadConfigXML())
+	assert.Equal(t, "XXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXX", apiKey)
+	assert.Equal(t, "http://localhost:8384", baseURL)
+
+	apiKey, baseURL = "", "https://syncthing.example.com:8384"
+	require.NoError(t, loadConfigXML())
+	assert.Empty(t, apiKey)
+	assert.Equal(t, "https://syncthing.example.com:8384", baseURL)
+
+	configXML = writeTestConfigXML(t, t.TempDir(), false, "")
+	apiKey, baseURL = "", ""
+	require.ErrorContains(t, loadConfigXML(), "empty GUI address")
+
+	configXML = filepath.Join(t.TempDir(), "config.xml")
+	require.NoError(t, os.WriteFile(configXML, []byte("<configuration>"), 0o600))
+	require.ErrorContains(t, loadConfigXML(), "parse syncthing config")
+}
+
+func TestLoadConfigXML_default(t *testing.T) {
+	saveTestGlobals(t)
+	stateHome := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", stateHome)
+	writeTestConfigXML(t, filepath.Join(stateHome, "syncthing"), false,
+		"/var/run/syncthing.sock")
+
+	require.NoError(t, loadConfigXML())
+	assert.Equal(t, "XXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXX", apiKey)
+	assert.Equal(t, "unix:///var/run/syncthing.sock", baseURL)
+}
+
+func TestLoadConfigXML_tls(t *testing.T) {
+	saveTestGlobals(t)
+	dir := t.TempDir()
+	configXML = writeTestConfigXML(t, dir, true, "127.0.0.1:8384")
+
+	require.NoError(t, loadConfigXML())
+	assert.Equal(t, "https://127.0.0.1:8384/", baseURL)
+	assert.Empty(t, fingerprint)
+
+	certFile := filepath.Join(dir, httpsCertName)
+	require.NoError(t, os.WriteFile(certFile, []byte("foobar"), 0o600))
+	apiKey, baseURL = "", ""
+	require.ErrorContains(t, loadConfigXML(), "no certificate found")
+
+	cert := []byte("some certificate")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(
+		&pem.Block{Type: "CERTIFICATE", Bytes: cert}), 0o600))
+	apiKey, baseURL = "", ""
+	require.NoError(t, loadConfigXML())
+	sum := sha256.Sum256(cert)
+	assert.Equal(t, hex.EncodeToString(sum[:]), fingerprint)
+
+	apiKey, baseURL, fingerprint, insecure = "", "", "", true
+	require.NoError(t, loadConfigXML())
+	assert.Empty(t, fingerprint)
+}
+
+func TestLocalURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{url: "http://127.0.0.1:8384/", want: true},
+		{url: "https://[::1]:8384/", want: true},
+		{url: "http://localhost:8384", want: true},
+		{url: "unix:///var/run/syncthing.sock", want: true},
+		{url: "unixs:///var/run/syncthing.sock", want: true},
+		{url: "http://192.168.1.1:8384/"},
+		{url: "https://syncthing.example.com"},
+		{url: "http://[::1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			assert.Equal(t, tt.want, localURL(tt.url))
+		})
+	}
+}
+
+func TestGuiConfig_URL(t *testing.T) {
+	tests := []struct {
+		addr    string
+		tls     bool
+		wantURL string
+	}{
+		{addr: "127.0.0.1:8384", wantURL: "http://127.0.0.1:8384/"},
+		{addr: "127.0.0.1:8384", tls: true, wantURL: "https://127.0.0.1:8384/"},
+		{addr: "0.0.0.0:8384", wantURL: "http://127.0.0.1:8384/"},
+		{addr: ":8384", wantURL: "http://127.0.0.1:8384/"},
+		{addr: "[::]:8384", wantURL: "http://[::1]:8384/"},
+		{addr: "localhost:8384", wantURL: "http://localhost:8384/"},
+		{addr: "https://0.0.0.0:8384", wantURL: "https://127.0.0.1:8384"},
+		{addr: "/var/run/st.sock", wantURL: "unix:///var/run/st.sock"},
+		{addr: "/var/run/st.sock", tls: true, wantURL: "unixs:///var/run/st.sock"},
+		{addr: "unixs:///var/run/st.sock", wantURL: "unixs:///var/run/st.sock"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.wantURL, func(t *testing.T) {
+			gui := guiConfig{TLS: tt.tls, Address: tt.addr}
+			u, err := gui.URL()
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantURL, u)
+		})
+	}
+
+	gui := guiConfig{Address: "http://[::1"}
+	_, err := gui.URL()
+	require.ErrorContains(t, err, "parse GUI address")
+}
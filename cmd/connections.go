@@ -0,0 +1,360 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dsh2dsh/go-monitoringplugin/v2"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/dsh2dsh/check_syncthing/client"
+	"github.com/dsh2dsh/check_syncthing/client/api"
+)
+
+const connsOkMsg = " devices connected" // N/N devices connected
+
+var (
+	requiredDevices                             []string
+	relayStatus, notLocalStatus, numConnsStatus int
+)
+
+var connectionsCmd = cobra.Command{
+	Use:   "connections",
+	Short: "Check required syncthing devices are connected",
+	Long: `Check required syncthing devices are connected.
+
+It outputs critical status for every required device, which isn't connected,
+with last dial error of its configured addresses. Required devices are given by
+--device, as device IDs, short IDs or names, or all configured and not paused
+devices are required if --device isn't given.
+
+Connected devices are checked for quality of their connections too. It outputs
+status given by --relay for devices connected via relay, status given by
+--not-local for devices connected from a LAN address, but not as local ones,
+and status given by --num-connections for devices with fewer parallel
+connections than configured. Status "ok" disables a check.
+
+Outputs connected state and total in/out bytes of every required device as
+performance data.`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := checkContext(cmd)
+		defer cancel()
+		NewConnectionsCheck(mustAPIClient()).
+			WithExcludeDevices(excludeDevices).
+			WithDevices(requiredDevices).
+			WithQualityStatus(relayStatus, notLocalStatus, numConnsStatus).
+			Run(ctx).Response().OutputAndExit()
+	},
+}
+
+func init() {
+	connectionsCmd.Flags().StringArrayVarP(&requiredDevices, "device", "d",
+		[]string{}, "IDs, short IDs or names of required devices")
+	connectionsCmd.Flags().Var(
+		newStatusValue(monitoringplugin.WARNING, &relayStatus), "relay",
+		"status of devices connected via relay")
+	connectionsCmd.Flags().Var(
+		newStatusValue(monitoringplugin.WARNING, &notLocalStatus), "not-local",
+		"status of devices connected from LAN address not as local")
+	connectionsCmd.Flags().Var(
+		newStatusValue(monitoringplugin.WARNING, &numConnsStatus),
+		"num-connections",
+		"status of devices with fewer connections than configured")
+}
+
+func NewConnectionsCheck(apiClient *client.Client) *ConnectionsCheck {
+	c := &ConnectionsCheck{
+		client:         apiClient,
+		relayStatus:    relayStatus,
+		notLocalStatus: notLocalStatus,
+		numConnsStatus: numConnsStatus,
+	}
+	return c.applyOptions()
+}
+
+type ConnectionsCheck struct {
+	client *client.Client
+	resp   *monitoringplugin.Response
+
+	required       []string
+	excludeDevices lookupDeviceId
+	relayStatus    int
+	notLocalStatus int
+	numConnsStatus int
+
+	system  *api.SystemStatus
+	devices []api.DeviceConfiguration
+	conns   *api.Connections
+}
+
+func (self *ConnectionsCheck) applyOptions() *ConnectionsCheck {
+	if self.resp == nil {
+		self.resp = monitoringplugin.NewResponse(connsOkMsg)
+	}
+	return self
+}
+
+func (self *ConnectionsCheck) WithExcludeDevices(devices []string,
+) *ConnectionsCheck {
+	self.excludeDevices = newLookupDeviceId(devices)
+	return self
+}
+
+// WithDevices configures required devices, given by short IDs or names. All
+// configured and not paused devices are required, if devices is empty.
+func (self *ConnectionsCheck) WithDevices(devices []string) *ConnectionsCheck {
+	self.required = devices
+	return self
+}
+
+// WithQualityStatus configures status of devices connected via relay, devices
+// connected from LAN address not as local ones and devices with fewer
+// connections than configured. OK status disables the check.
+func (self *ConnectionsCheck) WithQualityStatus(relay, notLocal, numConns int,
+) *ConnectionsCheck {
+	self.relayStatus = relay
+	self.notLocalStatus = notLocal
+	self.numConnsStatus = numConns
+	return self
+}
+
+func (self *ConnectionsCheck) Response() *monitoringplugin.Response {
+	return self.resp
+}
+
+func (self *ConnectionsCheck) Run(ctx context.Context) *ConnectionsCheck {
+	defer outputRetries(self.resp, self.client)
+
+	if !self.fetch(ctx) {
+		return self
+	}
+
+	devices, ok := self.requiredDevices()
+	if !ok {
+		return self
+	}
+
+	var connected int
+	for _, device := range devices {
+		if self.checkDevice(device) {
+			connected++
+		}
+	}
+
+	self.resp.WithDefaultOkMessage(strconv.Itoa(connected) + "/" +
+		strconv.Itoa(len(devices)) + connsOkMsg)
+	self.outputExcluded()
+	return self
+}
+
+func (self *ConnectionsCheck) fetch(parentCtx context.Context) bool {
+	g, ctx := errgroup.WithContext(parentCtx)
+	g.SetLimit(fetchProcs)
+
+	g.Go(func() error { return self.fetchSystemStatus(ctx) })
+	g.Go(func() error { return self.fetchDevices(ctx) })
+	g.Go(func() error { return self.fetchConns(ctx) })
+
+	// Disconnected devices can't be told apart after timeout.
+	return checkFetchError(parentCtx, self.resp, g.Wait()) &&
+		self.resp.GetStatusCode() == monitoringplugin.OK
+}
+
+func (self *ConnectionsCheck) fetchSystemStatus(ctx context.Context) error {
+	system, err := self.client.SystemStatus(ctx)
+	if err != nil {
+		return newFetchError("system status", err)
+	}
+	self.system = system
+	return nil
+}
+
+func (self *ConnectionsCheck) fetchDevices(ctx context.Context) error {
+	devices, err := self.client.Devices(ctx)
+	if err != nil {
+		return newFetchError("devices", err)
+	}
+	self.devices = devices
+	return nil
+}
+
+func (self *ConnectionsCheck) fetchConns(ctx context.Context) error {
+	conns, err := self.client.Connections(ctx)
+	if err != nil {
+		return newFetchError("connections", err)
+	}
+	self.conns = conns
+	return nil
+}
+
+// requiredDevices returns configured devices, which must be connected, in
+// order of configuration. It returns false and outputs UNKNOWN status, if some
+// of required devices aren't configured.
+func (self *ConnectionsCheck) requiredDevices() ([]*api.DeviceConfiguration,
+	bool,
+) {
+	devices := make([]*api.DeviceConfiguration, 0, len(self.devices))
+	if len(self.required) == 0 {
+		for i := range self.devices {
+			d := &self.devices[i]
+			if d.DeviceID != self.system.MyID && !d.Paused &&
+				!self.excludeDevices.Has(d.DeviceID) {
+				devices = append(devices, d)
+			}
+		}
+		return devices, true
+	}
+
+	var unknown []string
+	for _, name := range self.required {
+		idx := slices.IndexFunc(self.devices, func(d api.DeviceConfiguration) bool {
+			return d.Name == name || d.DeviceID == name ||
+				newDeviceId(d.DeviceID).Short() == name
+		})
+		if idx < 0 {
+			unknown = append(unknown, name)
+		} else if d := &self.devices[idx]; !slices.Contains(devices, d) &&
+			!self.excludeDevices.Has(d.DeviceID) {
+			devices = append(devices, d)
+		}
+	}
+
+	if len(unknown) > 0 {
+		self.resp.UpdateStatus(monitoringplugin.UNKNOWN,
+			"unknown device(s): "+strings.Join(unknown, ", "))
+		return nil, false
+	}
+	return devices, true
+}
+
+func (self *ConnectionsCheck) checkDevice(device *api.DeviceConfiguration,
+) bool {
+	name := deviceName(device.DeviceID, device.Name)
+	conn := self.conns.Connections[device.DeviceID]
+	switch {
+	case conn.Connected:
+		self.checkQuality(name, device, &conn)
+	case device.Paused || conn.Paused:
+		self.resp.UpdateStatus(monitoringplugin.WARNING,
+			"device "+name+" paused")
+	default:
+		msg := "device " + name + " disconnected"
+		if dialErr := self.lastDialError(device); dialErr != "" {
+			msg += ": " + dialErr
+		}
+		self.resp.UpdateStatus(monitoringplugin.CRITICAL, msg)
+	}
+
+	self.addPerfData(device, &conn)
+	return conn.Connected
+}
+
+func (self *ConnectionsCheck) checkQuality(name string,
+	device *api.DeviceConfiguration, conn *api.ConnectionStats,
+) {
+	relayed := strings.HasPrefix(conn.Type, "relay-")
+	if relayed && self.relayStatus != monitoringplugin.OK {
+		self.resp.UpdateStatus(self.relayStatus,
+			"device "+name+" connected via relay: "+conn.Address)
+	}
+
+	if !relayed && !conn.IsLocal && isPrivateAddr(conn.Address) &&
+		self.notLocalStatus != monitoringplugin.OK {
+		self.resp.UpdateStatus(self.notLocalStatus,
+			"device "+name+" connected not as local: "+conn.Address)
+	}
+
+	numConns := 1 + len(conn.Secondary)
+	if numConns < device.NumConnections &&
+		self.numConnsStatus != monitoringplugin.OK {
+		self.resp.UpdateStatus(self.numConnsStatus, fmt.Sprintf(
+			"device %v uses %v/%v connections", name, numConns,
+			device.NumConnections))
+	}
+}
+
+// isPrivateAddr returns true if host of addr is a loopback, private or link
+// local IP address.
+func isPrivateAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast()
+}
+
+// lastDialError returns the most recent dial error of configured addresses of
+// device. Dynamic addresses aren't known to syncthing config, so there's no
+// dial error for devices with dynamic addresses only.
+func (self *ConnectionsCheck) lastDialError(device *api.DeviceConfiguration,
+) string {
+	var last api.ConnectionStatusEntry
+	for _, addr := range device.Addresses {
+		status, ok := self.system.LastDialStatus[addr]
+		if ok && status.Error != "" && status.When.After(last.When) {
+			last = status
+		}
+	}
+
+	if last.Error == "" {
+		return ""
+	}
+	return last.Error + " (" + last.When.Format(time.DateTime) + ")"
+}
+
+func (self *ConnectionsCheck) addPerfData(device *api.DeviceConfiguration,
+	conn *api.ConnectionStats,
+) {
+	label := perfLabel(newDeviceId(device.DeviceID).Short())
+	var connected int
+	if conn.Connected {
+		connected = 1
+	}
+
+	point := monitoringplugin.NewPerformanceDataPoint("connected", connected).
+		SetLabel(label)
+	if err := self.resp.AddPerformanceDataPoint(point); err != nil {
+		self.resp.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "", true)
+	}
+
+	for _, p := range [...]struct {
+		metric string
+		value  int64
+	}{
+		{"in_bytes", conn.InBytesTotal},
+		{"out_bytes", conn.OutBytesTotal},
+	} {
+		point := monitoringplugin.NewPerformanceDataPoint(p.metric, p.value).
+			SetUnit("c").SetLabel(label)
+		if err := self.resp.AddPerformanceDataPoint(point); err != nil {
+			self.resp.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "", true)
+		}
+	}
+}
+
+func (self *ConnectionsCheck) outputExcluded() {
+	if !self.excludeDevices.Excluded() {
+		return
+	}
+
+	devices := make(map[string]api.DeviceConfiguration, len(self.devices))
+	for i := range self.devices {
+		d := &self.devices[i]
+		devices[d.DeviceID] = self.devices[i]
+	}
+	self.resp.UpdateStatus(self.resp.GetStatusCode(),
+		"excluded: "+self.excludeDevices.ExcludedString(devices))
+}
@@ -0,0 +1,272 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dsh2dsh/go-monitoringplugin/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dsh2dsh/check_syncthing/client/api"
+)
+
+func TestNewConnectionsCheck(t *testing.T) {
+	check := testNewConnectionsCheck(t, nil)
+	resp := check.Response()
+	require.NotNil(t, resp)
+	assert.Equal(t, "OK: "+connsOkMsg, resp.GetInfo().RawOutput)
+}
+
+func testNewConnectionsCheck(t *testing.T, endpoints map[string]any,
+) *ConnectionsCheck {
+	check := NewConnectionsCheck(newTestClient(t, fakeAPI(t, endpoints)))
+	require.NotNil(t, check)
+	return check
+}
+
+func TestConnectionsCheck_applyOptionsWithResp(t *testing.T) {
+	resp := monitoringplugin.NewResponse("def ok msg")
+	check := &ConnectionsCheck{resp: resp}
+	require.Same(t, check, check.applyOptions())
+	assert.Same(t, resp, check.Response())
+}
+
+func TestConnectionsCheck_Run(t *testing.T) {
+	const testId1 = "XXXXXX1-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX"
+	const testId2 = "XXXXXX2-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX"
+	const testId3 = "XXXXXX3-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX"
+	const testId4 = "XXXXXX4-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX"
+
+	devices := []api.DeviceConfiguration{
+		{DeviceID: testId1, Name: "server"},
+		{DeviceID: testId2, Name: "nas", Addresses: []string{
+			"tcp://10.0.0.2:22000", "quic://10.0.0.2:22000",
+		}},
+		{DeviceID: testId3, Name: "pc3", Addresses: []string{"dynamic"}},
+		{DeviceID: testId4, Name: "pc4", Paused: true},
+	}
+
+	dialTime := time.Date(2024, 3, 28, 20, 15, 11, 0, time.UTC)
+	system := api.SystemStatus{
+		MyID: testId1,
+		LastDialStatus: map[string]api.ConnectionStatusEntry{
+			"tcp://10.0.0.2:22000": {
+				Error: "i/o timeout",
+				When:  dialTime.Add(-time.Minute),
+			},
+			"quic://10.0.0.2:22000": {
+				Error: "connection refused",
+				When:  dialTime,
+			},
+		},
+	}
+
+	connected := api.Connections{
+		Connections: map[string]api.ConnectionStats{
+			testId2: {Connected: true, InBytesTotal: 100, OutBytesTotal: 200},
+			testId3: {Connected: true, InBytesTotal: 300, OutBytesTotal: 400},
+			testId4: {Paused: true},
+		},
+	}
+
+	disconnected := api.Connections{
+		Connections: map[string]api.ConnectionStats{
+			testId2: {InBytesTotal: 100, OutBytesTotal: 200},
+			testId4: {Paused: true},
+		},
+	}
+
+	const perfData = "'connected_XXXXXX2'=1 'in_bytes_XXXXXX2'=100c " +
+		"'out_bytes_XXXXXX2'=200c 'connected_XXXXXX3'=1 'in_bytes_XXXXXX3'=300c " +
+		"'out_bytes_XXXXXX3'=400c"
+
+	tests := []struct {
+		name      string
+		with      func(t *testing.T, check *ConnectionsCheck)
+		endpoints map[string]any
+		expected  string
+		contains  bool
+	}{
+		{
+			name:     "without endpoints",
+			expected: "UNKNOWN: /rest/",
+			contains: true,
+		},
+		{
+			name: "OK all",
+			endpoints: map[string]any{
+				"/rest/system/status":      system,
+				"/rest/config/devices":     devices,
+				"/rest/system/connections": connected,
+			},
+			expected: "OK: 2/2" + connsOkMsg + " | " + perfData,
+		},
+		{
+			name: "OK required",
+			with: func(t *testing.T, check *ConnectionsCheck) {
+				check.WithDevices([]string{"nas", "XXXXXX2"})
+			},
+			endpoints: map[string]any{
+				"/rest/system/status":      system,
+				"/rest/config/devices":     devices,
+				"/rest/system/connections": connected,
+			},
+			expected: "OK: 1/1" + connsOkMsg + " | 'connected_XXXXXX2'=1 " +
+				"'in_bytes_XXXXXX2'=100c 'out_bytes_XXXXXX2'=200c",
+		},
+		{
+			name: "OK required by ID",
+			with: func(t *testing.T, check *ConnectionsCheck) {
+				check.WithDevices([]string{testId2})
+			},
+			endpoints: map[string]any{
+				"/rest/system/status":      system,
+				"/rest/config/devices":     devices,
+				"/rest/system/connections": connected,
+			},
+			expected: "OK: 1/1" + connsOkMsg + " | 'connected_XXXXXX2'=1 " +
+				"'in_bytes_XXXXXX2'=100c 'out_bytes_XXXXXX2'=200c",
+		},
+		{
+			name: "OK excluded",
+			with: func(t *testing.T, check *ConnectionsCheck) {
+				check.WithExcludeDevices([]string{"XXXXXX3"})
+			},
+			endpoints: map[string]any{
+				"/rest/system/status":      system,
+				"/rest/config/devices":     devices,
+				"/rest/system/connections": disconnected,
+			},
+			expected: `CRITICAL: device XXXXXX2 (nas) disconnected: connection refused (2024-03-28 20:15:11)
+excluded: XXXXXX3 (pc3) | 'connected_XXXXXX2'=0 'in_bytes_XXXXXX2'=100c 'out_bytes_XXXXXX2'=200c`,
+		},
+		{
+			name: "disconnected",
+			endpoints: map[string]any{
+				"/rest/system/status":      system,
+				"/rest/config/devices":     devices,
+				"/rest/system/connections": disconnected,
+			},
+			expected: `CRITICAL: device XXXXXX2 (nas) disconnected: connection refused (2024-03-28 20:15:11)
+device XXXXXX3 (pc3) disconnected | 'connected_XXXXXX2'=0 'in_bytes_XXXXXX2'=100c 'out_bytes_XXXXXX2'=200c 'connected_XXXXXX3'=0 'in_bytes_XXXXXX3'=0c 'out_bytes_XXXXXX3'=0c`,
+		},
+		{
+			name: "paused required",
+			with: func(t *testing.T, check *ConnectionsCheck) {
+				check.WithDevices([]string{"pc4"})
+			},
+			endpoints: map[string]any{
+				"/rest/system/status":      system,
+				"/rest/config/devices":     devices,
+				"/rest/system/connections": connected,
+			},
+			expected: "WARNING: device XXXXXX4 (pc4) paused | 'connected_XXXXXX4'=0 " +
+				"'in_bytes_XXXXXX4'=0c 'out_bytes_XXXXXX4'=0c",
+		},
+		{
+			name: "quality",
+			endpoints: map[string]any{
+				"/rest/system/status": system,
+				"/rest/config/devices": []api.DeviceConfiguration{
+					{DeviceID: testId2, Name: "nas", NumConnections: 3},
+					{DeviceID: testId3, Name: "pc3"},
+				},
+				"/rest/system/connections": api.Connections{
+					Connections: map[string]api.ConnectionStats{
+						testId2: {
+							Connected: true,
+							Address:   "10.0.0.2:22000",
+							Type:      "tcp-client",
+							Secondary: []api.ConnectionInfo{{}},
+						},
+						testId3: {
+							Connected: true,
+							Address:   "203.0.113.1:22067",
+							Type:      "relay-client",
+						},
+					},
+				},
+			},
+			expected: `WARNING: device XXXXXX2 (nas) connected not as local: 10.0.0.2:22000
+device XXXXXX2 (nas) uses 2/3 connections
+device XXXXXX3 (pc3) connected via relay: 203.0.113.1:22067 | 'connected_XXXXXX2'=1 'in_bytes_XXXXXX2'=0c 'out_bytes_XXXXXX2'=0c 'connected_XXXXXX3'=1 'in_bytes_XXXXXX3'=0c 'out_bytes_XXXXXX3'=0c`,
+		},
+		{
+			name: "quality status",
+			with: func(t *testing.T, check *ConnectionsCheck) {
+				check.WithQualityStatus(monitoringplugin.CRITICAL,
+					monitoringplugin.OK, monitoringplugin.OK)
+			},
+			endpoints: map[string]any{
+				"/rest/system/status": system,
+				"/rest/config/devices": []api.DeviceConfiguration{
+					{DeviceID: testId2, Name: "nas", NumConnections: 3},
+				},
+				"/rest/system/connections": api.Connections{
+					Connections: map[string]api.ConnectionStats{
+						testId2: {
+							Connected: true,
+							Address:   "192.168.1.2:22067",
+							Type:      "relay-client",
+						},
+					},
+				},
+			},
+			expected: "CRITICAL: device XXXXXX2 (nas) connected via relay: " +
+				"192.168.1.2:22067 | 'connected_XXXXXX2'=1 " +
+				"'in_bytes_XXXXXX2'=0c 'out_bytes_XXXXXX2'=0c",
+		},
+		{
+			name: "unknown device",
+			with: func(t *testing.T, check *ConnectionsCheck) {
+				check.WithDevices([]string{"nas", "backup", "XXXXXX5"})
+			},
+			endpoints: map[string]any{
+				"/rest/system/status":      system,
+				"/rest/config/devices":     devices,
+				"/rest/system/connections": connected,
+			},
+			expected: "UNKNOWN: unknown device(s): backup, XXXXXX5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := testNewConnectionsCheck(t, tt.endpoints)
+			if tt.with != nil {
+				tt.with(t, check)
+			}
+			require.Same(t, check, check.Run(t.Context()))
+			rawOutput := check.Response().GetInfo().RawOutput
+			t.Log(rawOutput)
+			if tt.contains {
+				assert.Contains(t, rawOutput, tt.expected)
+			} else {
+				assert.Equal(t, tt.expected, rawOutput)
+			}
+		})
+	}
+}
+
+func TestIsPrivateAddr(t *testing.T) {
+	assert.True(t, isPrivateAddr("10.0.0.2:22000"))
+	assert.True(t, isPrivateAddr("[fe80::1]:22000"))
+	assert.True(t, isPrivateAddr("127.0.0.1"))
+	assert.False(t, isPrivateAddr("203.0.113.1:22000"))
+	assert.False(t, isPrivateAddr("example.com:22000"))
+}
+
+func TestConnectionsCheck_Run_timeout(t *testing.T) {
+	check := testNewConnectionsCheck(t, map[string]any{
+		"/rest/system/status":      api.SystemStatus{},
+		"/rest/config/devices":     []api.DeviceConfiguration{},
+		"/rest/system/connections": blockingAPI,
+	})
+
+	ctx := withTestTimeout(t, 50*time.Millisecond)
+	require.Same(t, check, check.Run(ctx))
+	assert.Equal(t,
+		"UNKNOWN: timed out after 50ms while fetching connections",
+		check.Response().GetInfo().RawOutput)
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// newCountValue returns flag value, which sets p to threshold of number of
+// items, given as absolute number, like 100, or percentage of all items, like
+// 10%.
+func newCountValue(val countThreshold, p *countThreshold) *countValue {
+	*p = val
+	return (*countValue)(p)
+}
+
+type countValue countThreshold
+
+func (self *countValue) String() string { return countThreshold(*self).String() }
+
+func (self *countValue) Set(s string) error {
+	t, err := parseCountThreshold(s)
+	if err != nil {
+		return err
+	}
+	*self = countValue(t)
+	return nil
+}
+
+func (self *countValue) Type() string { return "count" }
+
+func parseCountThreshold(s string) (countThreshold, error) {
+	num, percent := strings.CutSuffix(s, "%")
+	value, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return countThreshold{}, fmt.Errorf("parse count %q: %w", s, err)
+	} else if math.IsNaN(value) || math.IsInf(value, 0) {
+		return countThreshold{}, fmt.Errorf("count %q not a finite number", s)
+	} else if percent && (value < 0 || value > 100) {
+		return countThreshold{}, fmt.Errorf(
+			"count %q out of range 0..100%%", s)
+	} else if value < 0 || (!percent && value != math.Trunc(value)) {
+		return countThreshold{}, fmt.Errorf(
+			"count %q not an integer or percentage", s)
+	}
+	return countThreshold{value: value, percent: percent}, nil
+}
+
+// countThreshold is a threshold of number of items, absolute or percentage of
+// all items. Zero threshold is disabled.
+type countThreshold struct {
+	value   float64
+	percent bool
+}
+
+func (self countThreshold) String() string {
+	s := strconv.FormatFloat(self.value, 'f', -1, 64)
+	if self.percent {
+		return s + "%"
+	}
+	return s
+}
+
+// Exceeded returns true, if n of total items is more than threshold.
+func (self countThreshold) Exceeded(n, total int) bool {
+	switch {
+	case self.value == 0:
+		return false
+	case !self.percent:
+		return float64(n) > self.value
+	case total == 0:
+		return false
+	}
+	return float64(n)*100/float64(total) > self.value
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountValue(t *testing.T) {
+	var threshold countThreshold
+	value := newCountValue(countThreshold{value: 10, percent: true}, &threshold)
+	assert.Equal(t, countThreshold{value: 10, percent: true}, threshold)
+	assert.Equal(t, "10%", value.String())
+	assert.Equal(t, "count", value.Type())
+
+	require.NoError(t, value.Set("100"))
+	assert.Equal(t, countThreshold{value: 100}, threshold)
+	assert.Equal(t, "100", value.String())
+
+	require.Error(t, value.Set("foo"))
+	assert.Equal(t, countThreshold{value: 100}, threshold)
+}
+
+func TestParseCountThreshold(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected countThreshold
+		wantErr  bool
+	}{
+		{s: "0", expected: countThreshold{}},
+		{s: "100", expected: countThreshold{value: 100}},
+		{s: "10%", expected: countThreshold{value: 10, percent: true}},
+		{s: "2.5%", expected: countThreshold{value: 2.5, percent: true}},
+		{s: "2.5", wantErr: true},
+		{s: "-1", wantErr: true},
+		{s: "%", wantErr: true},
+		{s: "100%", expected: countThreshold{value: 100, percent: true}},
+		{s: "101%", wantErr: true},
+		{s: "-1%", wantErr: true},
+		{s: "NaN%", wantErr: true},
+		{s: "NaN", wantErr: true},
+		{s: "Inf%", wantErr: true},
+		{s: "+Inf", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			threshold, err := parseCountThreshold(tt.s)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, threshold)
+		})
+	}
+}
+
+func TestCountThreshold_Exceeded(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold countThreshold
+		n, total  int
+		expected  bool
+	}{
+		{name: "disabled", n: 100, total: 100},
+		{name: "count", threshold: countThreshold{value: 10}, n: 11, expected: true},
+		{name: "count equal", threshold: countThreshold{value: 10}, n: 10},
+		{
+			name:      "percent",
+			threshold: countThreshold{value: 10, percent: true},
+			n:         11, total: 100,
+			expected: true,
+		},
+		{
+			name:      "percent equal",
+			threshold: countThreshold{value: 10, percent: true},
+			n:         10, total: 100,
+		},
+		{
+			name:      "percent without total",
+			threshold: countThreshold{value: 10, percent: true},
+			n:         10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.threshold.Exceeded(tt.n, tt.total))
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dsh2dsh/go-monitoringplugin/v2"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/dsh2dsh/check_syncthing/client"
+	"github.com/dsh2dsh/check_syncthing/client/api"
+)
+
+// States of syncthing folder, which mean the folder is stopped.
+const (
+	folderStateError   = "error"
+	folderStateStopped = "stopped"
+)
+
+var dbStatusCmd = cobra.Command{
+	Use:   "db-status",
+	Short: "Check local state of syncthing folders",
+	Long: `Check local state of syncthing folders.
+
+Checks local state of every folder and outputs critical status for folders in
+"error" or "stopped" state, with the state and the reason. Outputs warning status for folders with failed
+filesystem watcher, pull errors or invalid folders. Paused folders are
+skipped.
+
+Outputs need bytes and need files of every folder as performance data.`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := checkContext(cmd)
+		defer cancel()
+		NewDBStatusCheck(mustAPIClient()).Run(ctx).Response().OutputAndExit()
+	},
+}
+
+func NewDBStatusCheck(apiClient *client.Client) *DBStatusCheck {
+	c := &DBStatusCheck{client: apiClient}
+	return c.applyOptions()
+}
+
+type DBStatusCheck struct {
+	client *client.Client
+	resp   *monitoringplugin.Response
+
+	folders  []api.FolderConfiguration
+	statuses []*api.FolderStatus
+}
+
+func (self *DBStatusCheck) applyOptions() *DBStatusCheck {
+	if self.resp == nil {
+		self.resp = monitoringplugin.NewResponse(foldersOkMsg)
+	}
+	return self
+}
+
+func (self *DBStatusCheck) Response() *monitoringplugin.Response {
+	return self.resp
+}
+
+func (self *DBStatusCheck) Run(ctx context.Context) *DBStatusCheck {
+	defer outputRetries(self.resp, self.client)
+
+	if !self.fetch(ctx) {
+		return self
+	}
+
+	self.resp.WithDefaultOkMessage(strconv.Itoa(len(self.folders)) + foldersOkMsg)
+	for i := range self.folders {
+		if status := self.statuses[i]; status != nil {
+			self.checkStatus(&self.folders[i], status)
+		}
+	}
+	self.outputPaused()
+	return self
+}
+
+func (self *DBStatusCheck) fetch(parentCtx context.Context) bool {
+	folders, err := self.client.Folders(parentCtx)
+	if !checkFetchError(parentCtx, self.resp, newFetchError("folders", err)) ||
+		err != nil {
+		return false
+	}
+	self.folders = folders
+
+	g, ctx := errgroup.WithContext(parentCtx)
+	g.SetLimit(fetchProcs)
+
+	self.statuses = make([]*api.FolderStatus, len(folders))
+	for i := range folders {
+		folder := &folders[i]
+		if ctx.Err() != nil {
+			break
+		} else if folder.Paused {
+			continue
+		}
+		g.Go(func() error {
+			status, err := self.client.FolderStatus(ctx, folder.Id)
+			if err != nil {
+				return newFetchError("status of folder "+folder.Id,
+					fmt.Errorf("status folder=%q: %w", folderName(folder), err))
+			}
+			self.statuses[i] = status
+			return nil
+		})
+	}
+	return checkFetchError(parentCtx, self.resp, g.Wait())
+}
+
+func (self *DBStatusCheck) checkStatus(folder *api.FolderConfiguration,
+	status *api.FolderStatus,
+) {
+	name := folderName(folder)
+	switch status.State {
+	case folderStateError, folderStateStopped:
+		self.resp.UpdateStatus(monitoringplugin.CRITICAL,
+			"folder "+name+" "+status.State+": "+status.Error)
+	}
+
+	if status.WatchError != "" {
+		self.resp.UpdateStatus(monitoringplugin.WARNING,
+			"folder "+name+" watcher failed: "+status.WatchError)
+	}
+
+	if status.PullErrors > 0 {
+		self.resp.UpdateStatus(monitoringplugin.WARNING, fmt.Sprintf(
+			"folder %v: %v pull error(s)", name, status.PullErrors))
+	}
+
+	if status.Invalid != nil && *status.Invalid != "" {
+		self.resp.UpdateStatus(monitoringplugin.WARNING,
+			"folder "+name+" invalid: "+*status.Invalid)
+	}
+
+	label := perfLabel(folder.Id)
+	bytesPoint := monitoringplugin.NewPerformanceDataPoint("need_bytes",
+		status.NeedBytes).SetUnit("B").SetLabel(label)
+	if err := self.resp.AddPerformanceDataPoint(bytesPoint); err != nil {
+		self.resp.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "", true)
+	}
+
+	filesPoint := monitoringplugin.NewPerformanceDataPoint("need_files",
+		status.NeedFiles).SetLabel(label)
+	if err := self.resp.AddPerformanceDataPoint(filesPoint); err != nil {
+		self.resp.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "", true)
+	}
+}
+
+func (self *DBStatusCheck) outputPaused() {
+	paused := make([]string, 0, len(self.folders))
+	for i := range self.folders {
+		if folder := &self.folders[i]; folder.Paused {
+			paused = append(paused, folderName(folder))
+		}
+	}
+
+	if len(paused) > 0 {
+		self.resp.UpdateStatus(monitoringplugin.OK,
+			"paused: "+strings.Join(paused, ", "))
+	}
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dsh2dsh/go-monitoringplugin/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dsh2dsh/check_syncthing/client/api"
+)
+
+func TestNewDBStatusCheck(t *testing.T) {
+	check := testNewDBStatusCheck(t, nil)
+	resp := check.Response()
+	require.NotNil(t, resp)
+	assert.Equal(t, "OK: "+foldersOkMsg, resp.GetInfo().RawOutput)
+}
+
+func testNewDBStatusCheck(t *testing.T, endpoints map[string]any,
+) *DBStatusCheck {
+	check := NewDBStatusCheck(newTestClient(t, fakeAPI(t, endpoints)))
+	require.NotNil(t, check)
+	return check
+}
+
+func TestDBStatusCheck_applyOptionsWithResp(t *testing.T) {
+	resp := monitoringplugin.NewResponse("def ok msg")
+	check := &DBStatusCheck{resp: resp}
+	require.Same(t, check, check.applyOptions())
+	assert.Same(t, resp, check.Response())
+}
+
+func TestDBStatusCheck_Run(t *testing.T) {
+	folders := []api.FolderConfiguration{
+		{Id: "default", Label: "Default Folder"},
+	}
+
+	const statusEP = "/rest/db/status?folder=default"
+	const perfData = "'need_bytes_default'=1024B 'need_files_default'=2"
+	invalid := "folder marker missing"
+
+	tests := []struct {
+		name      string
+		endpoints map[string]any
+		expected  string
+		contains  bool
+	}{
+		{
+			name:     "without endpoints",
+			expected: "UNKNOWN: /rest/",
+			contains: true,
+		},
+		{
+			name: "without status",
+			endpoints: map[string]any{
+				"/rest/config/folders": folders,
+			},
+			expected: "UNKNOWN: /rest/",
+			contains: true,
+		},
+		{
+			name: "OK",
+			endpoints: map[string]any{
+				"/rest/config/folders": folders,
+				statusEP: api.FolderStatus{
+					State: "idle", NeedBytes: 1024, NeedFiles: 2,
+				},
+			},
+			expected: "OK: 1" + foldersOkMsg + " | " + perfData,
+		},
+		{
+			name: "paused",
+			endpoints: map[string]any{
+				"/rest/config/folders": []api.FolderConfiguration{
+					{Id: "default", Label: "Default Folder"},
+					{Id: "paused", Label: "Paused Folder", Paused: true},
+				},
+				statusEP: api.FolderStatus{
+					State: "idle", NeedBytes: 1024, NeedFiles: 2,
+				},
+			},
+			expected: "OK: 2" + foldersOkMsg + "\npaused: paused (Paused Folder) | " +
+				perfData,
+		},
+		{
+			name: "error",
+			endpoints: map[string]any{
+				"/rest/config/folders": folders,
+				statusEP: api.FolderStatus{
+					State:     folderStateError,
+					Error:     "folder path missing",
+					NeedBytes: 1024, NeedFiles: 2,
+				},
+			},
+			expected: "CRITICAL: folder default (Default Folder) error: " +
+				"folder path missing | " + perfData,
+		},
+		{
+			name: "stopped",
+			endpoints: map[string]any{
+				"/rest/config/folders": folders,
+				statusEP: api.FolderStatus{
+					State:     folderStateStopped,
+					Error:     "folder marker missing",
+					NeedBytes: 1024, NeedFiles: 2,
+				},
+			},
+			expected: "CRITICAL: folder default (Default Folder) stopped: " +
+				"folder marker missing | " + perfData,
+		},
+		{
+			name: "warnings",
+			endpoints: map[string]any{
+				"/rest/config/folders": folders,
+				statusEP: api.FolderStatus{
+					State:      "idle",
+					WatchError: "too many files",
+					PullErrors: 3,
+					Invalid:    &invalid,
+					NeedBytes:  1024, NeedFiles: 2,
+				},
+			},
+			expected: `WARNING: folder default (Default Folder) watcher failed: too many files
+folder default (Default Folder): 3 pull error(s)
+folder default (Default Folder) invalid: folder marker missing | ` + perfData,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := testNewDBStatusCheck(t, tt.endpoints)
+			require.Same(t, check, check.Run(t.Context()))
+			rawOutput := check.Response().GetInfo().RawOutput
+			t.Log(rawOutput)
+			if tt.contains {
+				assert.Contains(t, rawOutput, tt.expected)
+			} else {
+				assert.Equal(t, tt.expected, rawOutput)
+			}
+		})
+	}
+}
+
+func TestDBStatusCheck_Run_timeout(t *testing.T) {
+	check := testNewDBStatusCheck(t, map[string]any{
+		"/rest/config/folders": []api.FolderConfiguration{
+			{Id: "default", Label: "Default Folder"},
+		},
+		"/rest/db/status?folder=default": blockingAPI,
+	})
+
+	ctx := withTestTimeout(t, 50*time.Millisecond)
+	require.Same(t, check, check.Run(ctx))
+	assert.Equal(t,
+		"UNKNOWN: timed out after 50ms while fetching status of folder default",
+		check.Response().GetInfo().RawOutput)
+}
@@ -0,0 +1,438 @@
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/dsh2dsh/go-monitoringplugin/v2"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/dsh2dsh/check_syncthing/client"
+	"github.com/dsh2dsh/check_syncthing/client/api"
+)
+
+const (
+	deletionsOkMsg = "no mass deletions"
+
+	diskActionDeleted = "deleted"
+)
+
+var (
+	warnDeletions, critDeletions countThreshold
+	deletionsWindow              time.Duration
+)
+
+var deletionsCmd = cobra.Command{
+	Use:   "deletions",
+	Short: "Check mass deletions in syncthing folders",
+	Long: `Check mass deletions in syncthing folders.
+
+It checks number of pending deletions of every folder and device, which shares
+it, and number of files deleted locally or by remote devices during --window.
+It outputs warning or critical status, if any of them is more than given
+thresholds. Thresholds are absolute number of items, like 100, or percentage
+of global items of the folder, like 10%. Zero threshold disables it. Zero
+--window disables checking of deleted files.
+
+Deleted files are counted using disk events, which syncthing keeps in memory
+and loses after restart. Syncthing keeps only last 1000 disk events. If disk
+events during --window were dropped, because of that limit, the event buffer
+overflowed and deleted files are counted since the oldest kept event only, as
+a lower bound, with warning status.
+
+Outputs total number of pending deletions and deleted files as performance
+data.`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := checkContext(cmd)
+		defer cancel()
+		NewDeletionsCheck(mustAPIClient()).
+			WithExcludeDevices(excludeDevices).
+			WithExcludeFolders(excludeFolders).
+			WithThresholds(warnDeletions, critDeletions).
+			WithWindow(deletionsWindow).
+			Run(ctx).Response().OutputAndExit()
+	},
+}
+
+func init() {
+	deletionsCmd.Flags().VarP(newCountValue(countThreshold{}, &warnDeletions),
+		"warn", "w", "warning threshold, like 100 or 10%")
+	deletionsCmd.Flags().VarP(
+		newCountValue(countThreshold{value: 10, percent: true}, &critDeletions),
+		"crit", "c", "critical threshold, like 100 or 10%")
+	deletionsCmd.Flags().DurationVar(&deletionsWindow, "window", time.Hour,
+		"count files deleted during that time")
+	deletionsCmd.Flags().StringArrayVar(&excludeFolders, "exclude-folder",
+		[]string{}, "IDs of folders to exclude")
+}
+
+func NewDeletionsCheck(apiClient *client.Client) *DeletionsCheck {
+	c := &DeletionsCheck{
+		client:        apiClient,
+		warnThreshold: warnDeletions,
+		critThreshold: critDeletions,
+		window:        deletionsWindow,
+	}
+	return c.applyOptions()
+}
+
+type DeletionsCheck struct {
+	client *client.Client
+	resp   *monitoringplugin.Response
+
+	excludeDevices lookupDeviceId
+	excludeFolders lookupFolderId
+	warnThreshold  countThreshold
+	critThreshold  countThreshold
+	window         time.Duration
+
+	devices  map[string]api.DeviceConfiguration
+	folders  []*api.FolderConfiguration
+	statuses []*api.FolderStatus // by index of folders
+	pairs    []deletionsPair
+	events   []api.Event
+}
+
+// deletionsPair is a folder and a device, which shares it, with completion of
+// the folder on the device.
+type deletionsPair struct {
+	folder   *api.FolderConfiguration
+	deviceId string
+	comp     *api.FolderCompletion
+}
+
+func (self *DeletionsCheck) applyOptions() *DeletionsCheck {
+	if self.resp == nil {
+		self.resp = monitoringplugin.NewResponse(deletionsOkMsg)
+	}
+	return self
+}
+
+func (self *DeletionsCheck) WithExcludeDevices(devices []string,
+) *DeletionsCheck {
+	self.excludeDevices = newLookupDeviceId(devices)
+	return self
+}
+
+func (self *DeletionsCheck) WithExcludeFolders(folders []string,
+) *DeletionsCheck {
+	self.excludeFolders = newLookupFolderId(folders)
+	return self
+}
+
+func (self *DeletionsCheck) WithThresholds(warn, crit countThreshold,
+) *DeletionsCheck {
+	self.warnThreshold = warn
+	self.critThreshold = crit
+	return self
+}
+
+// WithWindow configures how long ago files could be deleted to be counted.
+// Zero window disables counting of deleted files.
+func (self *DeletionsCheck) WithWindow(d time.Duration) *DeletionsCheck {
+	self.window = d
+	return self
+}
+
+func (self *DeletionsCheck) Response() *monitoringplugin.Response {
+	return self.resp
+}
+
+func (self *DeletionsCheck) Run(ctx context.Context) *DeletionsCheck {
+	defer outputRetries(self.resp, self.client)
+
+	if !self.fetch(ctx) {
+		return self
+	}
+
+	self.checkPending()
+	if self.window > 0 {
+		self.checkDeleted()
+	}
+	self.outputExcluded()
+	return self
+}
+
+func (self *DeletionsCheck) fetch(parentCtx context.Context) bool {
+	g, ctx := errgroup.WithContext(parentCtx)
+	g.SetLimit(fetchProcs)
+
+	g.Go(func() error { return self.fetchDevices(ctx) })
+	g.Go(func() error { return self.fetchFolders(ctx) })
+	if self.window > 0 {
+		g.Go(func() error { return self.fetchEvents(ctx) })
+	}
+
+	if !checkFetched(parentCtx, self.resp, g.Wait()) {
+		return false
+	}
+
+	g, ctx = errgroup.WithContext(parentCtx)
+	g.SetLimit(fetchProcs)
+	if self.window > 0 {
+		self.fetchStatuses(ctx, g)
+	}
+	self.fetchCompletions(ctx, g)
+	return checkFetchError(parentCtx, self.resp, g.Wait())
+}
+
+func (self *DeletionsCheck) fetchDevices(ctx context.Context) error {
+	devices, err := self.client.Devices(ctx)
+	if err != nil {
+		return newFetchError("devices", err)
+	}
+
+	self.devices = make(map[string]api.DeviceConfiguration, len(devices))
+	for i := range devices {
+		d := &devices[i]
+		self.devices[d.DeviceID] = devices[i]
+	}
+	return nil
+}
+
+func (self *DeletionsCheck) fetchFolders(ctx context.Context) error {
+	folders, err := self.client.Folders(ctx)
+	if err != nil {
+		return newFetchError("folders", err)
+	}
+
+	for i := range folders {
+		folder := &folders[i]
+		if !folder.Paused && !self.excludeFolders.Has(folder) {
+			self.folders = append(self.folders, folder)
+		}
+	}
+	return nil
+}
+
+func (self *DeletionsCheck) fetchEvents(ctx context.Context) error {
+	events, err := self.client.DiskEvents(ctx, &client.EventsQuery{})
+	if err != nil {
+		return newFetchError("disk events", err)
+	}
+	self.events = events
+	return nil
+}
+
+// fetchStatuses fetches local status of every folder, with number of its global
+// items, because a folder can have no devices to fetch completion.
+func (self *DeletionsCheck) fetchStatuses(ctx context.Context,
+	g *errgroup.Group,
+) {
+	self.statuses = make([]*api.FolderStatus, len(self.folders))
+	for i, folder := range self.folders {
+		if ctx.Err() != nil {
+			return
+		}
+		g.Go(func() error {
+			status, err := self.client.FolderStatus(ctx, folder.Id)
+			if err != nil {
+				return newFetchError("status of folder "+folder.Id,
+					fmt.Errorf("status folder=%q: %w", folderName(folder), err))
+			}
+			self.statuses[i] = status
+			return nil
+		})
+	}
+}
+
+func (self *DeletionsCheck) fetchCompletions(ctx context.Context,
+	g *errgroup.Group,
+) {
+	for _, folder := range self.folders {
+		for j := range folder.Devices {
+			deviceId := folder.Devices[j].DeviceId
+			if !self.excludeDevices.Has(deviceId) {
+				self.pairs = append(self.pairs, deletionsPair{
+					folder:   folder,
+					deviceId: deviceId,
+				})
+			}
+		}
+	}
+
+	for i := range self.pairs {
+		if ctx.Err() != nil {
+			return
+		}
+		pair := &self.pairs[i]
+		g.Go(func() error {
+			comp, err := self.client.Completion(ctx, pair.folder.Id, pair.deviceId)
+			if err != nil {
+				return newFetchError("completion of folder "+pair.folder.Id,
+					fmt.Errorf("completion folder=%q, device=%q: %w",
+						folderName(pair.folder), self.deviceName(pair.deviceId), err))
+			}
+			pair.comp = comp
+			return nil
+		})
+	}
+}
+
+func (self *DeletionsCheck) deviceName(id string) string {
+	return deviceName(id, self.devices[id].Name)
+}
+
+func (self *DeletionsCheck) checkPending() {
+	var total int
+	for i := range self.pairs {
+		pair := &self.pairs[i]
+		if pair.comp == nil {
+			continue
+		}
+		total += pair.comp.NeedDeletes
+
+		status := self.status(pair.comp.NeedDeletes, pair.comp.GlobalItems)
+		if status != monitoringplugin.OK {
+			self.resp.UpdateStatus(status, fmt.Sprintf(
+				"folder %v, device %v: %v pending deletion(s) of %v items",
+				folderName(pair.folder), self.deviceName(pair.deviceId),
+				pair.comp.NeedDeletes, pair.comp.GlobalItems))
+		}
+	}
+	self.addPerfData("need_deletes", total)
+}
+
+func (self *DeletionsCheck) status(n, total int) int {
+	switch {
+	case self.critThreshold.Exceeded(n, total):
+		return monitoringplugin.CRITICAL
+	case self.warnThreshold.Exceeded(n, total):
+		return monitoringplugin.WARNING
+	}
+	return monitoringplugin.OK
+}
+
+func (self *DeletionsCheck) checkDeleted() {
+	since := time.Now().Add(-self.window)
+	deleted, total := self.countDeleted(since)
+	var atLeast string
+	if self.eventsDropped(since) {
+		atLeast = "at least "
+		self.resp.UpdateStatus(monitoringplugin.WARNING,
+			"event buffer overflowed, deleted files are a lower bound, "+
+				"counted since "+self.events[0].Time.Format(time.DateTime))
+	}
+
+	keys := slices.SortedFunc(maps.Keys(deleted), func(a, b deletedKey) int {
+		return cmp.Or(cmp.Compare(a.folder.Id, b.folder.Id),
+			cmp.Compare(a.deviceId, b.deviceId))
+	})
+
+	for _, key := range keys {
+		n, globalItems := deleted[key], self.globalItems(key.folder)
+		status := self.status(n, globalItems)
+		if status != monitoringplugin.OK {
+			self.resp.UpdateStatus(status, fmt.Sprintf(
+				"folder %v, device %v: %v%v deleted in last %v of %v items",
+				folderName(key.folder), self.deviceName(key.deviceId), atLeast,
+				n, self.window, globalItems))
+		}
+	}
+	self.addPerfData("deleted", total)
+}
+
+// eventsDropped returns true, if syncthing dropped disk events after since,
+// because it keeps limited number of them. Kept events are numbered from 1
+// and the oldest of them is inside of the window in that case.
+func (self *DeletionsCheck) eventsDropped(since time.Time) bool {
+	return len(self.events) > 0 && self.events[0].Id > 1 &&
+		!self.events[0].Time.Before(since)
+}
+
+// countDeleted returns number of deleted files since given time, by folder and
+// device, which deleted them, and total number of them.
+func (self *DeletionsCheck) countDeleted(since time.Time,
+) (map[deletedKey]int, int) {
+	folders := make(map[string]*api.FolderConfiguration, len(self.folders))
+	for _, folder := range self.folders {
+		folders[folder.Id] = folder
+	}
+
+	deleted := make(map[deletedKey]int)
+	var total int
+	for i := range self.events {
+		e := &self.events[i]
+		if e.Time.Before(since) {
+			continue
+		}
+
+		data, err := client.DecodeEventData[api.DiskEventData](e)
+		if self.resp.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "",
+			true) {
+			continue
+		} else if data.Action != diskActionDeleted {
+			continue
+		}
+
+		folder, ok := folders[data.FolderID]
+		if !ok {
+			continue
+		}
+
+		deviceId := self.fullDeviceId(data.ModifiedBy)
+		if self.excludeDevices.Has(deviceId) {
+			continue
+		}
+		deleted[deletedKey{folder: folder, deviceId: deviceId}]++
+		total++
+	}
+	return deleted, total
+}
+
+// fullDeviceId returns full ID of configured device with given short ID, or
+// shortId itself, if there is no such device.
+func (self *DeletionsCheck) fullDeviceId(shortId *string) string {
+	if shortId == nil {
+		return ""
+	}
+	for id := range self.devices {
+		if newDeviceId(id).Short() == *shortId {
+			return id
+		}
+	}
+	return *shortId
+}
+
+// globalItems returns number of global items of folder from its local status,
+// or zero, if it wasn't fetched, like after timeout.
+func (self *DeletionsCheck) globalItems(folder *api.FolderConfiguration) int {
+	i := slices.Index(self.folders, folder)
+	if i < 0 || self.statuses[i] == nil {
+		return 0
+	}
+	status := self.statuses[i]
+	return status.GlobalFiles + status.GlobalDirectories + status.GlobalSymlinks
+}
+
+func (self *DeletionsCheck) addPerfData(metric string, n int) {
+	point := monitoringplugin.NewPerformanceDataPoint(metric, n)
+	if err := self.resp.AddPerformanceDataPoint(point); err != nil {
+		self.resp.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "", true)
+	}
+}
+
+func (self *DeletionsCheck) outputExcluded() {
+	if self.excludeFolders.Excluded() {
+		self.resp.UpdateStatus(self.resp.GetStatusCode(),
+			"excluded: "+self.excludeFolders.ExcludedString())
+	}
+	if self.excludeDevices.Excluded() {
+		self.resp.UpdateStatus(self.resp.GetStatusCode(),
+			"excluded: "+self.excludeDevices.ExcludedString(self.devices))
+	}
+}
+
+// --------------------------------------------------
+
+// deletedKey is a folder and a device, which deleted files in it.
+type deletedKey struct {
+	folder   *api.FolderConfiguration
+	deviceId string
+}
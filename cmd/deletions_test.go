@@ -0,0 +1,290 @@
+package cmd
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/dsh2dsh/go-monitoringplugin/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dsh2dsh/check_syncthing/client"
+	"github.com/dsh2dsh/check_syncthing/client/api"
+)
+
+func TestNewDeletionsCheck(t *testing.T) {
+	check := testNewDeletionsCheck(t, nil)
+	resp := check.Response()
+	require.NotNil(t, resp)
+	assert.Equal(t, "OK: "+deletionsOkMsg, resp.GetInfo().RawOutput)
+}
+
+func testNewDeletionsCheck(t *testing.T, endpoints map[string]any,
+) *DeletionsCheck {
+	check := NewDeletionsCheck(newTestClient(t, fakeAPI(t, endpoints)))
+	require.NotNil(t, check)
+	return check
+}
+
+func TestDeletionsCheck_applyOptionsWithResp(t *testing.T) {
+	resp := monitoringplugin.NewResponse("def ok msg")
+	check := &DeletionsCheck{resp: resp}
+	require.Same(t, check, check.applyOptions())
+	assert.Same(t, resp, check.Response())
+}
+
+func TestDeletionsCheck_Run(t *testing.T) {
+	const testId1 = "XXXXXX1-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX"
+	const testId2 = "XXXXXX2-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX"
+
+	devices := []api.DeviceConfiguration{
+		{DeviceID: testId1, Name: "server"},
+		{DeviceID: testId2, Name: "laptop"},
+	}
+
+	folders := []api.FolderConfiguration{
+		{
+			Id: "docs", Label: "Docs",
+			Devices: []api.FolderDeviceConfiguration{
+				{DeviceId: testId1}, {DeviceId: testId2},
+			},
+		},
+		{
+			Id: "paused", Label: "Paused", Paused: true,
+			Devices: []api.FolderDeviceConfiguration{{DeviceId: testId1}},
+		},
+	}
+	localFolders := append(slices.Clone(folders),
+		api.FolderConfiguration{Id: "local", Label: "Local"})
+
+	const comp1EP = "/rest/db/completion?device=" + testId1 + "&folder=docs"
+	const comp2EP = "/rest/db/completion?device=" + testId2 + "&folder=docs"
+	const eventsEP = "/rest/events/disk?since=0&timeout=0"
+	const statusEP = "/rest/db/status?folder=docs"
+	const localStatusEP = "/rest/db/status?folder=local"
+
+	noDeletes := api.FolderCompletion{Completion: 100, GlobalItems: 100}
+	largeFolder := api.FolderCompletion{Completion: 100, GlobalItems: 100000}
+	status := api.FolderStatus{
+		GlobalFiles: 90, GlobalDirectories: 9, GlobalSymlinks: 1,
+	}
+	largeStatus := api.FolderStatus{GlobalFiles: 99000, GlobalDirectories: 1000}
+	deletes := api.FolderCompletion{
+		Completion: 80, GlobalItems: 100, NeedDeletes: 20, NeedItems: 20,
+	}
+
+	deletedEvent := func(t *testing.T, id int, at time.Time, modifiedBy string,
+		folderId string,
+	) api.Event {
+		data, err := json.Marshal(api.DiskEventData{
+			Folder: folderId, FolderID: folderId, Label: folderId,
+			Action: diskActionDeleted, Type: "file", Path: "file",
+			ModifiedBy: &modifiedBy,
+		})
+		require.NoError(t, err)
+		return api.Event{
+			Id: id, GlobalID: id, Time: at, Data: data,
+			Type: client.EventRemoteChangeDetected,
+		}
+	}
+
+	now := time.Now()
+	events := make([]api.Event, 0, 13)
+	events = append(events, deletedEvent(t, 1, now.Add(-2*time.Hour), "XXXXXX1",
+		"docs"))
+	for i := range 12 {
+		events = append(events, deletedEvent(t, i+2, now, "XXXXXX2", "docs"))
+	}
+
+	localEvents := make([]api.Event, 0, 12)
+	for i := range 12 {
+		localEvents = append(localEvents,
+			deletedEvent(t, i+1, now, "XXXXXX1", "local"))
+	}
+
+	tests := []struct {
+		name      string
+		with      func(t *testing.T, check *DeletionsCheck)
+		endpoints map[string]any
+		expected  string
+		contains  bool
+	}{
+		{
+			name:     "without endpoints",
+			expected: "UNKNOWN: /rest/",
+			contains: true,
+		},
+		{
+			name: "without completion",
+			endpoints: map[string]any{
+				"/rest/config/devices": devices,
+				"/rest/config/folders": folders,
+				eventsEP:               []api.Event{},
+				statusEP:               status,
+				comp1EP:                noDeletes,
+			},
+			expected: "UNKNOWN: /rest/",
+			contains: true,
+		},
+		{
+			name: "OK",
+			endpoints: map[string]any{
+				"/rest/config/devices": devices,
+				"/rest/config/folders": folders,
+				eventsEP:               events[:1],
+				statusEP:               status,
+				comp1EP:                noDeletes,
+				comp2EP:                noDeletes,
+			},
+			expected: "OK: " + deletionsOkMsg +
+				" | 'need_deletes'=0 'deleted'=0",
+		},
+		{
+			name: "pending deletions",
+			endpoints: map[string]any{
+				"/rest/config/devices": devices,
+				"/rest/config/folders": folders,
+				eventsEP:               []api.Event{},
+				statusEP:               status,
+				comp1EP:                noDeletes,
+				comp2EP:                deletes,
+			},
+			expected: "CRITICAL: folder docs (Docs), device XXXXXX2 (laptop):" +
+				" 20 pending deletion(s) of 100 items" +
+				" | 'need_deletes'=20 'deleted'=0",
+		},
+		{
+			name: "deleted",
+			with: func(t *testing.T, check *DeletionsCheck) {
+				check.WithThresholds(countThreshold{value: 10},
+					countThreshold{value: 50})
+			},
+			endpoints: map[string]any{
+				"/rest/config/devices": devices,
+				"/rest/config/folders": folders,
+				eventsEP:               events,
+				statusEP:               status,
+				comp1EP:                noDeletes,
+				comp2EP:                noDeletes,
+			},
+			expected: "WARNING: folder docs (Docs), device XXXXXX2 (laptop):" +
+				" 12 deleted in last 1h0m0s of 100 items" +
+				" | 'need_deletes'=0 'deleted'=12",
+		},
+		{
+			name: "events dropped",
+			with: func(t *testing.T, check *DeletionsCheck) {
+				check.WithThresholds(countThreshold{value: 10},
+					countThreshold{value: 50})
+			},
+			endpoints: map[string]any{
+				"/rest/config/devices": devices,
+				"/rest/config/folders": folders,
+				eventsEP:               events[1:],
+				statusEP:               status,
+				comp1EP:                noDeletes,
+				comp2EP:                noDeletes,
+			},
+			expected: "WARNING: event buffer overflowed," +
+				" deleted files are a lower bound, counted since " +
+				now.Format(time.DateTime) +
+				"\nfolder docs (Docs), device XXXXXX2 (laptop):" +
+				" at least 12 deleted in last 1h0m0s of 100 items" +
+				" | 'need_deletes'=0 'deleted'=12",
+		},
+		{
+			name: "percentage of large folder",
+			with: func(t *testing.T, check *DeletionsCheck) {
+				check.WithThresholds(countThreshold{},
+					countThreshold{value: 1, percent: true})
+			},
+			endpoints: map[string]any{
+				"/rest/config/devices": devices,
+				"/rest/config/folders": folders,
+				eventsEP:               events,
+				statusEP:               largeStatus,
+				comp1EP:                largeFolder,
+				comp2EP:                largeFolder,
+			},
+			expected: "OK: " + deletionsOkMsg + " | 'need_deletes'=0 'deleted'=12",
+		},
+		{
+			name: "folder without devices",
+			endpoints: map[string]any{
+				"/rest/config/devices": devices,
+				"/rest/config/folders": localFolders,
+				eventsEP:               localEvents,
+				statusEP:               status,
+				localStatusEP:          status,
+				comp1EP:                noDeletes,
+				comp2EP:                noDeletes,
+			},
+			expected: "CRITICAL: folder local (Local), device XXXXXX1 (server):" +
+				" 12 deleted in last 1h0m0s of 100 items" +
+				" | 'need_deletes'=0 'deleted'=12",
+		},
+		{
+			name: "without window",
+			with: func(t *testing.T, check *DeletionsCheck) {
+				check.WithThresholds(countThreshold{value: 10},
+					countThreshold{value: 50}).WithWindow(0)
+			},
+			endpoints: map[string]any{
+				"/rest/config/devices": devices,
+				"/rest/config/folders": folders,
+				comp1EP:                noDeletes,
+				comp2EP:                noDeletes,
+			},
+			expected: "OK: " + deletionsOkMsg + " | 'need_deletes'=0",
+		},
+		{
+			name: "excluded",
+			with: func(t *testing.T, check *DeletionsCheck) {
+				check.WithExcludeDevices([]string{"XXXXXX2"}).
+					WithExcludeFolders([]string{"paused"})
+			},
+			endpoints: map[string]any{
+				"/rest/config/devices": devices,
+				"/rest/config/folders": folders,
+				eventsEP:               events,
+				statusEP:               status,
+				comp1EP:                noDeletes,
+			},
+			expected: "OK: " + deletionsOkMsg +
+				"\nexcluded: XXXXXX2 (laptop) | 'need_deletes'=0 'deleted'=0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := testNewDeletionsCheck(t, tt.endpoints)
+			if tt.with != nil {
+				tt.with(t, check)
+			}
+			require.Same(t, check, check.Run(t.Context()))
+			rawOutput := check.Response().GetInfo().RawOutput
+			t.Log(rawOutput)
+			if tt.contains {
+				assert.Contains(t, rawOutput, tt.expected)
+			} else {
+				assert.Equal(t, tt.expected, rawOutput)
+			}
+		})
+	}
+}
+
+func TestDeletionsCheck_Run_timeout(t *testing.T) {
+	check := testNewDeletionsCheck(t, map[string]any{
+		"/rest/config/devices":                []api.DeviceConfiguration{},
+		"/rest/config/folders":                []api.FolderConfiguration{},
+		"/rest/events/disk?since=0&timeout=0": blockingAPI,
+	})
+
+	ctx := withTestTimeout(t, 50*time.Millisecond)
+	require.Same(t, check, check.Run(ctx))
+	assert.Equal(t,
+		"UNKNOWN: timed out after 50ms while fetching disk events",
+		check.Response().GetInfo().RawOutput)
+}
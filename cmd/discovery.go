@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dsh2dsh/go-monitoringplugin/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/dsh2dsh/check_syncthing/client"
+	"github.com/dsh2dsh/check_syncthing/client/api"
+)
+
+const (
+	discoveryOkMsg = "discovery services"
+
+	discoveryGlobal = "global"
+	discoveryLocal  = "local"
+)
+
+var globalDiscoveryStatus, localDiscoveryStatus int
+
+var discoveryCmd = cobra.Command{
+	Use:   "discovery",
+	Short: "Check syncthing discovery services",
+	Long: `Check syncthing discovery services.
+
+It outputs errors of global and local discovery services with status given by
+--global and --local. Status "ok" ignores errors of that kind of services.
+
+Outputs number of working discovery services as performance data.`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := checkContext(cmd)
+		defer cancel()
+		NewDiscoveryCheck(mustAPIClient()).
+			WithStatus(globalDiscoveryStatus, localDiscoveryStatus).
+			Run(ctx).Response().OutputAndExit()
+	},
+}
+
+func init() {
+	discoveryCmd.Flags().Var(
+		newStatusValue(monitoringplugin.WARNING, &globalDiscoveryStatus),
+		"global", "status of global discovery errors")
+	discoveryCmd.Flags().Var(
+		newStatusValue(monitoringplugin.WARNING, &localDiscoveryStatus),
+		"local", "status of local discovery errors")
+}
+
+func NewDiscoveryCheck(apiClient *client.Client) *DiscoveryCheck {
+	c := &DiscoveryCheck{client: apiClient}
+	return c.WithStatus(globalDiscoveryStatus, localDiscoveryStatus).
+		applyOptions()
+}
+
+type DiscoveryCheck struct {
+	client *client.Client
+	resp   *monitoringplugin.Response
+
+	statuses map[string]int
+}
+
+func (self *DiscoveryCheck) applyOptions() *DiscoveryCheck {
+	if self.resp == nil {
+		self.resp = monitoringplugin.NewResponse(discoveryOkMsg)
+	}
+	return self
+}
+
+// WithStatus configures status of global and local discovery errors.
+func (self *DiscoveryCheck) WithStatus(global, local int) *DiscoveryCheck {
+	self.statuses = map[string]int{
+		discoveryGlobal: global,
+		discoveryLocal:  local,
+	}
+	return self
+}
+
+func (self *DiscoveryCheck) Response() *monitoringplugin.Response {
+	return self.resp
+}
+
+func (self *DiscoveryCheck) Run(ctx context.Context) *DiscoveryCheck {
+	defer outputRetries(self.resp, self.client)
+
+	system, err := self.client.SystemStatus(ctx)
+	if !checkFetchError(ctx, self.resp, newFetchError("system status", err)) ||
+		err != nil {
+		return self
+	}
+
+	if !system.DiscoveryEnabled {
+		self.resp.WithDefaultOkMessage("discovery disabled")
+		return self
+	}
+
+	services := newServiceEntries(system.DiscoveryStatus, discoveryKind,
+		func(e api.DiscoveryStatusEntry) string { return e.Error })
+	working := checkServices(self.resp, services, self.statuses)
+	self.resp.WithDefaultOkMessage(servicesOkMsg(working, len(services),
+		discoveryOkMsg))
+	return self
+}
+
+// discoveryKind returns kind of discovery service by its name, like
+// "global@https://discovery.syncthing.net/v2/" or "IPv4 local".
+func discoveryKind(name string) string {
+	if strings.HasPrefix(name, discoveryGlobal+"@") {
+		return discoveryGlobal
+	}
+	return discoveryLocal
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dsh2dsh/go-monitoringplugin/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dsh2dsh/check_syncthing/client/api"
+)
+
+func TestNewDiscoveryCheck(t *testing.T) {
+	check := testNewDiscoveryCheck(t, nil)
+	resp := check.Response()
+	require.NotNil(t, resp)
+	assert.Equal(t, "OK: "+discoveryOkMsg, resp.GetInfo().RawOutput)
+}
+
+func testNewDiscoveryCheck(t *testing.T, endpoints map[string]any,
+) *DiscoveryCheck {
+	check := NewDiscoveryCheck(newTestClient(t, fakeAPI(t, endpoints)))
+	require.NotNil(t, check)
+	return check
+}
+
+func TestDiscoveryCheck_applyOptionsWithResp(t *testing.T) {
+	resp := monitoringplugin.NewResponse("def ok msg")
+	check := &DiscoveryCheck{resp: resp}
+	require.Same(t, check, check.applyOptions())
+	assert.Same(t, resp, check.Response())
+}
+
+func TestDiscoveryCheck_Run(t *testing.T) {
+	const globalName = "global@https://discovery.syncthing.net/v2/"
+
+	system := api.SystemStatus{
+		DiscoveryEnabled: true,
+		DiscoveryStatus: map[string]api.DiscoveryStatusEntry{
+			globalName:   {},
+			"IPv4 local": {},
+			"IPv6 local": {Error: "listen udp6: socket: protocol not supported"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		with     func(t *testing.T, check *DiscoveryCheck)
+		system   any
+		expected string
+	}{
+		{
+			name: "OK",
+			system: api.SystemStatus{
+				DiscoveryEnabled: true,
+				DiscoveryStatus: map[string]api.DiscoveryStatusEntry{
+					globalName:   {},
+					"IPv4 local": {},
+				},
+			},
+			expected: "OK: 2/2 " + discoveryOkMsg + " working | 'working'=2;;;0;2",
+		},
+		{
+			name:     "disabled",
+			system:   api.SystemStatus{},
+			expected: "OK: discovery disabled",
+		},
+		{
+			name:   "local failed",
+			system: system,
+			expected: "WARNING: IPv6 local: listen udp6: socket: protocol not " +
+				"supported | 'working'=2;;;0;3",
+		},
+		{
+			name: "local ignored",
+			with: func(t *testing.T, check *DiscoveryCheck) {
+				check.WithStatus(monitoringplugin.CRITICAL, monitoringplugin.OK)
+			},
+			system:   system,
+			expected: "OK: 2/3 " + discoveryOkMsg + " working | 'working'=2;;;0;3",
+		},
+		{
+			name: "global failed",
+			with: func(t *testing.T, check *DiscoveryCheck) {
+				check.WithStatus(monitoringplugin.CRITICAL, monitoringplugin.OK)
+			},
+			system: api.SystemStatus{
+				DiscoveryEnabled: true,
+				DiscoveryStatus: map[string]api.DiscoveryStatusEntry{
+					globalName:   {Error: "i/o timeout"},
+					"IPv4 local": {},
+				},
+			},
+			expected: "CRITICAL: " + globalName + ": i/o timeout | " +
+				"'working'=1;;;0;2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := testNewDiscoveryCheck(t, map[string]any{
+				"/rest/system/status": tt.system,
+			})
+			if tt.with != nil {
+				tt.with(t, check)
+			}
+			require.Same(t, check, check.Run(t.Context()))
+			rawOutput := check.Response().GetInfo().RawOutput
+			t.Log(rawOutput)
+			assert.Equal(t, tt.expected, rawOutput)
+		})
+	}
+}
+
+func TestDiscoveryCheck_Run_timeout(t *testing.T) {
+	check := testNewDiscoveryCheck(t, map[string]any{
+		"/rest/system/status": blockingAPI,
+	})
+
+	ctx := withTestTimeout(t, 50*time.Millisecond)
+	require.Same(t, check, check.Run(ctx))
+	assert.Equal(t,
+		"UNKNOWN: timed out after 50ms while fetching system status",
+		check.Response().GetInfo().RawOutput)
+}
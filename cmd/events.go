@@ -0,0 +1,392 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dsh2dsh/go-monitoringplugin/v2"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/dsh2dsh/check_syncthing/client"
+	"github.com/dsh2dsh/check_syncthing/client/api"
+)
+
+const eventsOkMsg = "no events since last check"
+
+// eventTypes are known types of syncthing events, usable by --warn and --crit.
+var eventTypes = []string{
+	client.EventConfigSaved,
+	client.EventDeviceConnected,
+	client.EventDeviceDisconnected,
+	client.EventDevicePaused,
+	client.EventDeviceRejected,
+	client.EventFolderErrors,
+	client.EventFolderPaused,
+	client.EventFolderRejected,
+	client.EventFolderResumed,
+	client.EventLocalChangeDetected,
+	client.EventRemoteChangeDetected,
+	client.EventStarting,
+	client.EventStateChanged,
+}
+
+var (
+	eventsStateFile                string
+	warnEventTypes, critEventTypes []string
+	watchDevices                   []string
+)
+
+var eventsCmd = cobra.Command{
+	Use:   "events",
+	Short: "Check syncthing events since last check",
+	Long: `Check syncthing events since last check.
+
+It reads all syncthing events since previous run of the check and outputs
+warning or critical status for events of given types, so problems between
+check intervals, like flapping devices or transient pull failures, aren't
+missed.
+
+ID of last processed event is stored in --state-file between runs. The first
+run only initializes the state file. Only events of types given by --warn and
+--crit are requested from syncthing. Syncthing starts buffering events of these
+types on the first request of them, so events before it, like after restart of
+syncthing or changing of types, can't be checked. Syncthing buffers limited
+number of events and it outputs warning status, if some of them were lost
+between runs.
+
+DeviceDisconnected events are checked for devices given by --device only, or
+for all devices if --device isn't given.`,
+
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := validEventTypes("--warn", warnEventTypes); err != nil {
+			return err
+		}
+		return validEventTypes("--crit", critEventTypes)
+	},
+
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := checkContext(cmd)
+		defer cancel()
+		NewEventsCheck(mustAPIClient(), eventsStateFile).
+			WithExcludeDevices(excludeDevices).
+			WithDevices(watchDevices).
+			WithEventTypes(warnEventTypes, critEventTypes).
+			Run(ctx).Response().OutputAndExit()
+	},
+}
+
+func init() {
+	eventsCmd.Flags().StringVar(&eventsStateFile, "state-file", "",
+		"file with ID of last processed event")
+	eventsCmd.Flags().StringSliceVarP(&warnEventTypes, "warn", "w",
+		[]string{
+			client.EventConfigSaved,
+			client.EventDeviceDisconnected,
+			client.EventFolderErrors,
+			client.EventFolderPaused,
+		},
+		"event types with warning status")
+	eventsCmd.Flags().StringSliceVarP(&critEventTypes, "crit", "c", []string{},
+		"event types with critical status")
+	eventsCmd.Flags().StringArrayVarP(&watchDevices, "device", "d", []string{},
+		"short IDs of devices to check DeviceDisconnected events for")
+
+	cobra.CheckErr(eventsCmd.MarkFlagRequired("state-file"))
+}
+
+func validEventTypes(flag string, types []string) error {
+	for _, t := range types {
+		if !slices.Contains(eventTypes, t) {
+			return fmt.Errorf("%v %q not one of: %v", flag, t,
+				strings.Join(eventTypes, ", "))
+		}
+	}
+	return nil
+}
+
+func NewEventsCheck(apiClient *client.Client, stateFile string) *EventsCheck {
+	c := &EventsCheck{client: apiClient, stateFile: stateFile}
+	return c.applyOptions()
+}
+
+type EventsCheck struct {
+	client *client.Client
+	resp   *monitoringplugin.Response
+
+	stateFile      string
+	eventTypes     map[string]int
+	watchDevices   lookupDeviceId
+	excludeDevices lookupDeviceId
+
+	system  *api.SystemStatus
+	devices map[string]api.DeviceConfiguration
+	folders map[string]api.FolderConfiguration
+	cursor  *client.EventCursor
+	events  []api.Event
+}
+
+func (self *EventsCheck) applyOptions() *EventsCheck {
+	if self.resp == nil {
+		self.resp = monitoringplugin.NewResponse(eventsOkMsg)
+	}
+	return self
+}
+
+func (self *EventsCheck) WithExcludeDevices(devices []string) *EventsCheck {
+	self.excludeDevices = newLookupDeviceId(devices)
+	return self
+}
+
+func (self *EventsCheck) WithDevices(devices []string) *EventsCheck {
+	self.watchDevices = newLookupDeviceId(devices)
+	return self
+}
+
+// WithEventTypes configures event types with WARNING and CRITICAL status.
+// CRITICAL wins, if an event type is in both of them.
+func (self *EventsCheck) WithEventTypes(warn, crit []string) *EventsCheck {
+	self.eventTypes = make(map[string]int, len(warn)+len(crit))
+	for _, t := range warn {
+		self.eventTypes[t] = monitoringplugin.WARNING
+	}
+	for _, t := range crit {
+		self.eventTypes[t] = monitoringplugin.CRITICAL
+	}
+	return self
+}
+
+// types returns sorted event types configured by [EventsCheck.WithEventTypes].
+func (self *EventsCheck) types() []string {
+	return slices.Sorted(maps.Keys(self.eventTypes))
+}
+
+func (self *EventsCheck) Response() *monitoringplugin.Response {
+	return self.resp
+}
+
+func (self *EventsCheck) Run(ctx context.Context) *EventsCheck {
+	defer outputRetries(self.resp, self.client)
+
+	if !self.loadCursor() || !self.fetch(ctx) {
+		return self
+	}
+
+	if self.cursor.StartTime.IsZero() {
+		if self.initCursor(ctx) {
+			self.resp.WithDefaultOkMessage("initialized state file " +
+				self.stateFile)
+		}
+		return self
+	}
+
+	types := self.types()
+	since := self.cursor.Since(self.system.StartTime, types)
+	if !self.fetchEvents(ctx, &client.EventsQuery{Types: types, Since: since}) {
+		return self
+	}
+
+	self.checkLost(since)
+	self.checkEvents()
+	self.cursor.Update(self.events)
+	self.saveCursor()
+	return self
+}
+
+func (self *EventsCheck) loadCursor() bool {
+	cursor, err := client.LoadEventCursor(self.stateFile)
+	if self.resp.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "", true) {
+		return false
+	}
+	self.cursor = cursor
+	return true
+}
+
+func (self *EventsCheck) fetch(parentCtx context.Context) bool {
+	g, ctx := errgroup.WithContext(parentCtx)
+	g.SetLimit(fetchProcs)
+
+	g.Go(func() error { return self.fetchSystemStatus(ctx) })
+	g.Go(func() error { return self.fetchDevices(ctx) })
+	g.Go(func() error { return self.fetchFolders(ctx) })
+
+	return checkFetched(parentCtx, self.resp, g.Wait())
+}
+
+func (self *EventsCheck) fetchSystemStatus(ctx context.Context) error {
+	system, err := self.client.SystemStatus(ctx)
+	if err != nil {
+		return newFetchError("system status", err)
+	}
+	self.system = system
+	return nil
+}
+
+func (self *EventsCheck) fetchDevices(ctx context.Context) error {
+	devices, err := self.client.Devices(ctx)
+	if err != nil {
+		return newFetchError("devices", err)
+	}
+
+	self.devices = make(map[string]api.DeviceConfiguration, len(devices))
+	for i := range devices {
+		d := &devices[i]
+		self.devices[d.DeviceID] = devices[i]
+	}
+	return nil
+}
+
+func (self *EventsCheck) fetchFolders(ctx context.Context) error {
+	folders, err := self.client.Folders(ctx)
+	if err != nil {
+		return newFetchError("folders", err)
+	}
+
+	self.folders = make(map[string]api.FolderConfiguration, len(folders))
+	for i := range folders {
+		f := &folders[i]
+		self.folders[f.Id] = folders[i]
+	}
+	return nil
+}
+
+// initCursor moves new cursor to the last event, because old events were
+// already checked by something else or nobody cares about them.
+func (self *EventsCheck) initCursor(ctx context.Context) bool {
+	types := self.types()
+	self.cursor.Since(self.system.StartTime, types)
+	if !self.fetchEvents(ctx, &client.EventsQuery{Types: types, Limit: 1}) {
+		return false
+	}
+	self.cursor.Update(self.events)
+	return self.saveCursor()
+}
+
+// fetchEvents fetches events selected by q. Events after timeout are useless,
+// because the cursor can't skip unchecked events.
+func (self *EventsCheck) fetchEvents(ctx context.Context,
+	q *client.EventsQuery,
+) bool {
+	events, err := self.client.Events(ctx, q)
+	if err != nil {
+		checkFetchError(ctx, self.resp, newFetchError("events", err))
+		return false
+	}
+	self.events = events
+	return true
+}
+
+func (self *EventsCheck) saveCursor() bool {
+	err := self.cursor.Save()
+	return !self.resp.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "",
+		true)
+}
+
+// checkLost outputs WARNING, if events after since were lost, because they
+// overflowed limited buffer of syncthing between runs. Events can't be lost
+// after reset of the cursor, because syncthing starts buffering them on the
+// first request.
+func (self *EventsCheck) checkLost(since int) {
+	if since == 0 || len(self.events) == 0 {
+		return
+	} else if lost := self.events[0].Id - since - 1; lost > 0 {
+		self.resp.UpdateStatus(monitoringplugin.WARNING, fmt.Sprintf(
+			"%v event(s) lost, syncthing event buffer overflowed", lost))
+	}
+}
+
+func (self *EventsCheck) checkEvents() {
+	messages := make([]monitoringplugin.OutputMessage, 0, len(self.events))
+	worst := monitoringplugin.OK
+	for i := range self.events {
+		e := &self.events[i]
+		status, ok := self.eventTypes[e.Type]
+		if !ok {
+			continue
+		}
+
+		msg, ok := self.eventMessage(e)
+		if !ok {
+			continue
+		}
+		messages = append(messages, monitoringplugin.OutputMessage{
+			Status:  status,
+			Message: e.Time.Format(time.DateTime) + " " + msg,
+		})
+		worst = max(worst, status)
+	}
+
+	if len(messages) > 0 {
+		self.resp.UpdateStatus(worst, strconv.Itoa(len(messages))+
+			" event(s) since last check")
+		for _, m := range messages {
+			self.resp.UpdateStatus(m.Status, m.Message)
+		}
+	}
+
+	point := monitoringplugin.NewPerformanceDataPoint("events", len(messages))
+	if err := self.resp.AddPerformanceDataPoint(point); err != nil {
+		self.resp.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "", true)
+	}
+	self.outputExcluded()
+}
+
+func (self *EventsCheck) eventMessage(e *api.Event) (string, bool) {
+	switch e.Type {
+	case client.EventDeviceDisconnected:
+		data, err := client.DecodeEventData[api.DeviceDisconnectedEventData](e)
+		if err != nil {
+			return err.Error(), true
+		} else if !self.watchDevice(data.Id) {
+			return "", false
+		}
+		return e.Type + ": " + self.deviceName(data.Id) + ": " + data.Error, true
+	case client.EventFolderErrors:
+		data, err := client.DecodeEventData[api.FolderErrorsEventData](e)
+		if err != nil {
+			return err.Error(), true
+		} else if len(data.Errors) == 0 {
+			return e.Type + ": " + self.folderName(data.Folder), true
+		}
+		msg := fmt.Sprintf("%v: %v: %v error(s): %v", e.Type,
+			self.folderName(data.Folder), len(data.Errors), data.Errors[0].Error)
+		return msg, true
+	case client.EventFolderPaused:
+		data, err := client.DecodeEventData[api.FolderPausedEventData](e)
+		if err != nil {
+			return err.Error(), true
+		}
+		return e.Type + ": " + self.folderName(data.Id), true
+	}
+	return e.Type, true
+}
+
+func (self *EventsCheck) watchDevice(id string) bool {
+	if self.excludeDevices.Has(id) {
+		return false
+	}
+	return len(self.watchDevices.devices) == 0 || self.watchDevices.Has(id)
+}
+
+func (self *EventsCheck) deviceName(id string) string {
+	return deviceName(id, self.devices[id].Name)
+}
+
+func (self *EventsCheck) folderName(id string) string {
+	if folder, ok := self.folders[id]; ok {
+		return folderName(&folder)
+	}
+	return id
+}
+
+func (self *EventsCheck) outputExcluded() {
+	if self.excludeDevices.Excluded() {
+		self.resp.UpdateStatus(self.resp.GetStatusCode(),
+			"excluded: "+self.excludeDevices.ExcludedString(self.devices))
+	}
+}
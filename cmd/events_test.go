@@ -0,0 +1,310 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dsh2dsh/check_syncthing/client"
+	"github.com/dsh2dsh/check_syncthing/client/api"
+)
+
+func TestNewEventsCheck(t *testing.T) {
+	check := testNewEventsCheck(t, "", nil)
+	resp := check.Response()
+	require.NotNil(t, resp)
+	assert.Equal(t, "OK: "+eventsOkMsg, resp.GetInfo().RawOutput)
+}
+
+func TestEventsCmd_PreRunE(t *testing.T) {
+	origWarn, origCrit := warnEventTypes, critEventTypes
+	t.Cleanup(func() { warnEventTypes, critEventTypes = origWarn, origCrit })
+
+	warnEventTypes = []string{client.EventFolderErrors}
+	critEventTypes = []string{client.EventDeviceDisconnected}
+	require.NoError(t, eventsCmd.PreRunE(&eventsCmd, nil))
+
+	warnEventTypes = []string{"FolderError"}
+	require.ErrorContains(t, eventsCmd.PreRunE(&eventsCmd, nil),
+		`--warn "FolderError" not one of`)
+
+	warnEventTypes, critEventTypes = nil, []string{"foobar"}
+	require.ErrorContains(t, eventsCmd.PreRunE(&eventsCmd, nil),
+		`--crit "foobar" not one of`)
+}
+
+func testNewEventsCheck(t *testing.T, stateFile string,
+	endpoints map[string]any,
+) *EventsCheck {
+	check := NewEventsCheck(newTestClient(t, fakeAPI(t, endpoints)), stateFile).
+		WithEventTypes([]string{
+			client.EventConfigSaved,
+			client.EventDeviceDisconnected,
+			client.EventFolderErrors,
+			client.EventFolderPaused,
+		}, nil)
+	require.NotNil(t, check)
+	return check
+}
+
+func writeTestEventCursor(t *testing.T, lastId int, startTime time.Time,
+	types []string,
+) string {
+	fname := filepath.Join(t.TempDir(), "events.json")
+	b, err := json.Marshal(client.EventCursor{
+		LastID:    lastId,
+		StartTime: startTime,
+		Types:     types,
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(fname, b, 0o600))
+	return fname
+}
+
+func TestEventsCheck_Run(t *testing.T) {
+	const testId2 = "XXXXXX2-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX"
+	const testId4 = "XXXXXX4-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX"
+
+	startTime := time.Date(2024, 3, 28, 19, 0, 0, 0, time.UTC)
+	eventTime := time.Date(2024, 3, 28, 20, 15, 11, 0, time.UTC)
+
+	events := []api.Event{
+		{
+			Id:   2,
+			Type: client.EventDeviceDisconnected,
+			Time: eventTime,
+			Data: json.RawMessage(`{"id": "` + testId2 +
+				`", "error": "unexpected EOF"}`),
+		},
+		{
+			Id:   3,
+			Type: client.EventStateChanged,
+			Time: eventTime,
+			Data: json.RawMessage(`{"folder": "default", "from": "idle",
+"to": "scanning"}`),
+		},
+		{
+			Id:   4,
+			Type: client.EventFolderErrors,
+			Time: eventTime,
+			Data: json.RawMessage(`{"folder": "default", "errors": [
+{"error": "some error", "path": "/some file path"}]}`),
+		},
+		{
+			Id:   5,
+			Type: client.EventFolderPaused,
+			Time: eventTime,
+			Data: json.RawMessage(`{"id": "default", "label": "Default Folder"}`),
+		},
+		{
+			Id:   6,
+			Type: client.EventDeviceDisconnected,
+			Time: eventTime,
+			Data: json.RawMessage(`{"id": "` + testId4 +
+				`", "error": "reading length: EOF"}`),
+		},
+	}
+
+	endpoints := func(path string, events []api.Event) map[string]any {
+		return map[string]any{
+			"/rest/system/status": api.SystemStatus{StartTime: startTime},
+			"/rest/config/devices": []api.DeviceConfiguration{
+				{DeviceID: testId2, Name: "device2"},
+				{DeviceID: testId4, Name: "device4"},
+			},
+			"/rest/config/folders": []api.FolderConfiguration{
+				{Id: "default", Label: "Default Folder"},
+			},
+			path: events,
+		}
+	}
+
+	const warnTypes = "ConfigSaved%2CDeviceDisconnected%2CFolderErrors%2C" +
+		"FolderPaused"
+
+	tests := []struct {
+		name        string
+		lastId      int
+		startTime   time.Time
+		cursorTypes []string
+		with        func(check *EventsCheck)
+		endpoints   map[string]any
+		output      string
+		wantId      int
+	}{
+		{
+			name: "init state file",
+			endpoints: endpoints("/rest/events?events="+warnTypes+
+				"&limit=1&since=0&timeout=0", events[4:]),
+			output: "OK: initialized state file ",
+			wantId: 6,
+		},
+		{
+			name:      "without events",
+			lastId:    6,
+			startTime: startTime,
+			endpoints: endpoints("/rest/events?events="+warnTypes+
+				"&since=6&timeout=0", nil),
+			output: "OK: no events since last check | 'events'=0",
+			wantId: 6,
+		},
+		{
+			name:      "with events",
+			lastId:    1,
+			startTime: startTime,
+			endpoints: endpoints("/rest/events?events="+warnTypes+
+				"&since=1&timeout=0", events),
+			output: `WARNING: 4 event(s) since last check
+2024-03-28 20:15:11 DeviceDisconnected: XXXXXX2 (device2): unexpected EOF
+2024-03-28 20:15:11 FolderErrors: default (Default Folder): 1 error(s): some error
+2024-03-28 20:15:11 FolderPaused: default (Default Folder)
+2024-03-28 20:15:11 DeviceDisconnected: XXXXXX4 (device4): reading length: EOF | 'events'=4`,
+			wantId: 6,
+		},
+		{
+			name:      "critical events",
+			lastId:    1,
+			startTime: startTime,
+			with: func(check *EventsCheck) {
+				check.WithEventTypes([]string{client.EventFolderErrors},
+					[]string{client.EventDeviceDisconnected})
+			},
+			endpoints: endpoints(
+				"/rest/events?events=DeviceDisconnected%2CFolderErrors"+
+					"&since=1&timeout=0", events),
+			output: `CRITICAL: 3 event(s) since last check
+2024-03-28 20:15:11 DeviceDisconnected: XXXXXX2 (device2): unexpected EOF
+2024-03-28 20:15:11 DeviceDisconnected: XXXXXX4 (device4): reading length: EOF
+2024-03-28 20:15:11 FolderErrors: default (Default Folder): 1 error(s): some error | 'events'=3`,
+			wantId: 6,
+		},
+		{
+			name:      "watched devices",
+			lastId:    1,
+			startTime: startTime,
+			with: func(check *EventsCheck) {
+				check.WithEventTypes(nil, []string{client.EventDeviceDisconnected}).
+					WithDevices([]string{"XXXXXX4"})
+			},
+			endpoints: endpoints(
+				"/rest/events?events=DeviceDisconnected&since=1&timeout=0", events),
+			output: `CRITICAL: 1 event(s) since last check
+2024-03-28 20:15:11 DeviceDisconnected: XXXXXX4 (device4): reading length: EOF | 'events'=1`,
+			wantId: 6,
+		},
+		{
+			name:      "excluded devices",
+			lastId:    1,
+			startTime: startTime,
+			with: func(check *EventsCheck) {
+				check.WithEventTypes(nil, []string{client.EventDeviceDisconnected}).
+					WithExcludeDevices([]string{"XXXXXX4"})
+			},
+			endpoints: endpoints(
+				"/rest/events?events=DeviceDisconnected&since=1&timeout=0", events),
+			output: `CRITICAL: 1 event(s) since last check
+2024-03-28 20:15:11 DeviceDisconnected: XXXXXX2 (device2): unexpected EOF
+excluded: XXXXXX4 (device4) | 'events'=1`,
+			wantId: 6,
+		},
+		{
+			name:      "syncthing restarted",
+			lastId:    10,
+			startTime: startTime.Add(-time.Hour),
+			with: func(check *EventsCheck) {
+				check.WithEventTypes([]string{client.EventFolderPaused}, nil)
+			},
+			endpoints: endpoints(
+				"/rest/events?events=FolderPaused&since=0&timeout=0", events),
+			output: `WARNING: 1 event(s) since last check
+2024-03-28 20:15:11 FolderPaused: default (Default Folder) | 'events'=1`,
+			wantId: 6,
+		},
+		{
+			name:      "events lost",
+			lastId:    1,
+			startTime: startTime,
+			endpoints: endpoints("/rest/events?events="+warnTypes+
+				"&since=1&timeout=0", events[2:]),
+			output: `WARNING: 2 event(s) lost, syncthing event buffer overflowed
+3 event(s) since last check
+2024-03-28 20:15:11 FolderErrors: default (Default Folder): 1 error(s): some error
+2024-03-28 20:15:11 FolderPaused: default (Default Folder)
+2024-03-28 20:15:11 DeviceDisconnected: XXXXXX4 (device4): reading length: EOF | 'events'=3`,
+			wantId: 6,
+		},
+		{
+			name:        "event types changed",
+			lastId:      10,
+			startTime:   startTime,
+			cursorTypes: []string{client.EventFolderPaused},
+			endpoints: endpoints("/rest/events?events="+warnTypes+
+				"&since=0&timeout=0", events[4:]),
+			output: `WARNING: 1 event(s) since last check
+2024-03-28 20:15:11 DeviceDisconnected: XXXXXX4 (device4): reading length: EOF | 'events'=1`,
+			wantId: 6,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stateFile := filepath.Join(t.TempDir(), "events.json")
+			check := testNewEventsCheck(t, stateFile, tt.endpoints)
+			if tt.with != nil {
+				tt.with(check)
+			}
+
+			if !tt.startTime.IsZero() {
+				cursorTypes := tt.cursorTypes
+				if cursorTypes == nil {
+					cursorTypes = check.types()
+				}
+				stateFile = writeTestEventCursor(t, tt.lastId, tt.startTime,
+					cursorTypes)
+				check.stateFile = stateFile
+			}
+			require.Same(t, check, check.Run(t.Context()))
+			rawOutput := check.Response().GetInfo().RawOutput
+			t.Log(rawOutput)
+			if tt.startTime.IsZero() {
+				assert.Equal(t, tt.output+stateFile, rawOutput)
+			} else {
+				assert.Equal(t, tt.output, rawOutput)
+			}
+
+			cursor, err := client.LoadEventCursor(stateFile)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantId, cursor.LastID)
+			assert.True(t, startTime.Equal(cursor.StartTime))
+			assert.Equal(t, check.types(), cursor.Types)
+		})
+	}
+}
+
+func TestEventsCheck_Run_errors(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "events.json")
+	require.NoError(t, os.WriteFile(stateFile, []byte("{"), 0o600))
+	check := testNewEventsCheck(t, stateFile, nil)
+	require.Same(t, check, check.Run(t.Context()))
+	assert.Contains(t, check.Response().GetInfo().RawOutput,
+		"UNKNOWN: parse events state")
+
+	stateFile = writeTestEventCursor(t, 1, time.Time{}.Add(time.Hour), nil)
+	check = testNewEventsCheck(t, stateFile, map[string]any{
+		"/rest/system/status":  api.SystemStatus{},
+		"/rest/config/devices": []api.DeviceConfiguration{},
+		"/rest/config/folders": []api.FolderConfiguration{},
+	})
+	require.Same(t, check, check.Run(t.Context()))
+	assert.Contains(t, check.Response().GetInfo().RawOutput,
+		"UNKNOWN: /rest/events not found")
+
+	cursor, err := client.LoadEventCursor(stateFile)
+	require.NoError(t, err)
+	assert.Equal(t, 1, cursor.LastID, "state file not changed")
+}
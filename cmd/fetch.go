@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/dsh2dsh/go-monitoringplugin/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/dsh2dsh/check_syncthing/client"
+)
+
+// maxRetries is the max number of retries of failed requests.
+const maxRetries = 10
+
+var (
+	checkTimeout time.Duration
+
+	retries    int
+	retryDelay time.Duration
+)
+
+func init() {
+	rootCmd.PersistentFlags().DurationVarP(&checkTimeout, "timeout", "t", 0,
+		"timeout of the whole check, like 50s "+
+			"(0 means 15s timeout of every request)")
+	rootCmd.PersistentFlags().IntVar(&retries, "retries", 2,
+		"retry failed requests, except timeouts, that many times, up to "+
+			strconv.Itoa(maxRetries)+" (0 means no retries)")
+	rootCmd.PersistentFlags().DurationVar(&retryDelay, "retry-delay",
+		500*time.Millisecond, "delay before first retry, doubled after every retry")
+}
+
+// setCheckContext bounds context of cmd by --timeout. It's called once, before
+// --key-command, so the command and the check share the same deadline.
+func setCheckContext(cmd *cobra.Command) context.CancelFunc {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if checkTimeout > 0 {
+		ctx, cancel = context.WithTimeout(cmd.Context(), checkTimeout)
+	} else {
+		ctx, cancel = context.WithCancel(cmd.Context())
+	}
+	cmd.SetContext(ctx)
+	return cancel
+}
+
+// checkContext returns context of a check, which is bounded by --timeout,
+// given by [setCheckContext] already.
+func checkContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	return context.WithCancel(cmd.Context())
+}
+
+// outputRetries outputs number of retried requests, if any.
+func outputRetries(resp *monitoringplugin.Response, c *client.Client) {
+	if n := c.Retries(); n > 0 {
+		resp.UpdateStatus(monitoringplugin.OK, "retries: "+strconv.Itoa(n))
+	}
+}
+
+// --------------------------------------------------
+
+func newFetchError(what string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &fetchError{what: what, err: err}
+}
+
+// fetchError annotates err by what was fetching, when it happened.
+type fetchError struct {
+	what string
+	err  error
+}
+
+func (self *fetchError) Error() string { return self.err.Error() }
+
+func (self *fetchError) Unwrap() error { return self.err }
+
+// checkFetchError updates status of resp by err of fetching and returns true,
+// if the check can continue. It continues without errors and after timeout of
+// the check, given by ctx, with results fetched before it. Timeouts of single
+// requests are errors of syncthing daemon.
+//
+// Errors of syncthing daemon, like it's unavailable, are CRITICAL. Errors of
+// plugin configuration, like invalid API key or server URL, are UNKNOWN. Not
+// found is UNKNOWN too, because syncthing responds so for wrong server URL and
+// for unknown folder or device as well.
+func checkFetchError(ctx context.Context, resp *monitoringplugin.Response,
+	err error,
+) bool {
+	if err == nil {
+		return true
+	} else if ctx.Err() != nil && errors.Is(err, context.DeadlineExceeded) {
+		what := "data"
+		var fetchErr *fetchError
+		if errors.As(err, &fetchErr) {
+			what = fetchErr.what
+		}
+		resp.UpdateStatus(monitoringplugin.UNKNOWN,
+			"timed out after "+checkTimeout.String()+" while fetching "+what)
+		return true
+	}
+
+	var apiErr *client.APIError
+	switch {
+	case !errors.As(err, &apiErr) || apiErr.ServerError():
+		resp.UpdateStatusOnError(err, monitoringplugin.CRITICAL, "", true)
+	case apiErr.Unauthorized():
+		resp.UpdateStatusOnError(err, monitoringplugin.UNKNOWN,
+			"access denied, check API key", true)
+	case apiErr.NotFound():
+		resp.UpdateStatusOnError(err, monitoringplugin.UNKNOWN,
+			apiErr.Endpoint+" not found", true)
+	default:
+		resp.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "", true)
+	}
+	return false
+}
+
+// checkFetched is like [checkFetchError], but returns true only if everything
+// was fetched. A fetch, which timed out, leaves UNKNOWN status and something
+// unfetched, so there is nothing to fetch next or output without it.
+func checkFetched(ctx context.Context, resp *monitoringplugin.Response,
+	err error,
+) bool {
+	return checkFetchError(ctx, resp, err) &&
+		resp.GetStatusCode() == monitoringplugin.OK
+}
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dsh2dsh/go-monitoringplugin/v2"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dsh2dsh/check_syncthing/client"
+	"github.com/dsh2dsh/check_syncthing/client/api"
+)
+
+func TestCheckContext(t *testing.T) {
+	origTimeout := checkTimeout
+	t.Cleanup(func() { checkTimeout = origTimeout })
+
+	cmd := cobra.Command{}
+	cmd.SetContext(t.Context())
+
+	checkTimeout = 0
+	defer setCheckContext(&cmd)()
+	ctx, cancel := checkContext(&cmd)
+	_, ok := ctx.Deadline()
+	assert.False(t, ok)
+	cancel()
+	require.ErrorIs(t, ctx.Err(), context.Canceled)
+
+	cmd.SetContext(t.Context())
+	checkTimeout = time.Minute
+	defer setCheckContext(&cmd)()
+	deadline, ok := cmd.Context().Deadline()
+	require.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second)
+
+	ctx, cancel = checkContext(&cmd)
+	defer cancel()
+	ctxDeadline, ok := ctx.Deadline()
+	require.True(t, ok)
+	assert.Equal(t, deadline, ctxDeadline, "same deadline")
+}
+
+func TestCheckFetchError(t *testing.T) {
+	origTimeout := checkTimeout
+	t.Cleanup(func() { checkTimeout = origTimeout })
+	checkTimeout = 10 * time.Second
+
+	tests := []struct {
+		name    string
+		err     error
+		expired bool
+		proceed bool
+		status  int
+		output  string
+	}{
+		{
+			name:    "without error",
+			proceed: true,
+			status:  monitoringplugin.OK,
+			output:  "OK: OK",
+		},
+		{
+			name:   "with error",
+			err:    newFetchError("devices", errors.New("test error")),
+			status: monitoringplugin.CRITICAL,
+			output: "CRITICAL: test error",
+		},
+		{
+			name: "not found",
+			err: newFetchError("devices", &client.APIError{
+				Endpoint:   "/rest/config/devices",
+				StatusCode: http.StatusNotFound,
+				Status:     "404 Not Found",
+			}),
+			status: monitoringplugin.UNKNOWN,
+			output: "UNKNOWN: /rest/config/devices not found " +
+				"(error: unexpected syncthing response: 404 Not Found ())",
+		},
+		{
+			name: "bad request",
+			err: newFetchError("devices", &client.APIError{
+				StatusCode: http.StatusBadRequest,
+				Message:    "test error",
+			}),
+			status: monitoringplugin.UNKNOWN,
+			output: "UNKNOWN: unexpected syncthing error: test error",
+		},
+		{
+			name: "timeout",
+			err: newFetchError("devices",
+				errors.Join(errors.New("test error"), context.DeadlineExceeded)),
+			expired: true,
+			proceed: true,
+			status:  monitoringplugin.UNKNOWN,
+			output:  "UNKNOWN: timed out after 10s while fetching devices",
+		},
+		{
+			name:    "timeout without what",
+			err:     context.DeadlineExceeded,
+			expired: true,
+			proceed: true,
+			status:  monitoringplugin.UNKNOWN,
+			output:  "UNKNOWN: timed out after 10s while fetching data",
+		},
+		{
+			name:   "request timeout",
+			err:    newFetchError("devices", context.DeadlineExceeded),
+			status: monitoringplugin.CRITICAL,
+			output: "CRITICAL: context deadline exceeded",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+			if tt.expired {
+				expiredCtx, cancel := context.WithDeadline(ctx, time.Now())
+				defer cancel()
+				ctx = expiredCtx
+			}
+			resp := monitoringplugin.NewResponse("OK")
+			assert.Equal(t, tt.proceed, checkFetchError(ctx, resp, tt.err))
+			assert.Equal(t, tt.status, resp.GetStatusCode())
+			assert.Equal(t, tt.output, resp.GetInfo().RawOutput)
+		})
+	}
+}
+
+func TestCheckFetchError_clientTimeout(t *testing.T) {
+	origTimeout := checkTimeout
+	t.Cleanup(func() { checkTimeout = origTimeout })
+	checkTimeout = 0
+
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) { <-r.Context().Done() }))
+	t.Cleanup(srv.Close)
+
+	httpClient := &http.Client{Timeout: 50 * time.Millisecond}
+	c, err := client.New(srv.URL, func(c *client.Client) error {
+		return c.NewClientWithResponses(api.WithHTTPClient(httpClient))
+	})
+	require.NoError(t, err)
+
+	_, err = c.Devices(t.Context())
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	resp := monitoringplugin.NewResponse("OK")
+	assert.False(t, checkFetchError(t.Context(), resp,
+		newFetchError("devices", err)))
+	assert.Equal(t, monitoringplugin.CRITICAL, resp.GetStatusCode())
+	assert.NotContains(t, resp.GetInfo().RawOutput, "timed out after")
+}
+
+func TestNewFetchError(t *testing.T) {
+	require.NoError(t, newFetchError("devices", nil))
+
+	testErr := errors.New("test error")
+	err := newFetchError("devices", testErr)
+	require.ErrorIs(t, err, testErr)
+	assert.Equal(t, "test error", err.Error())
+}
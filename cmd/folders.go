@@ -2,9 +2,12 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/dsh2dsh/go-monitoringplugin/v2"
 	"github.com/spf13/cobra"
@@ -14,23 +17,211 @@ import (
 	"github.com/dsh2dsh/check_syncthing/client/api"
 )
 
-const foldersOkMsg = " syncthing folders" // N syncthing folders
+const (
+	foldersOkMsg = " syncthing folders" // N syncthing folders
+
+	folderErrorsPerPage = 1000
+
+	// Kinds of performance data of completions.
+	perfDataPairs = "pairs"
+	perfDataTotal = "total"
+
+	// Remote states of folders.
+	remoteNotSharing = "notSharing"
+	remoteUnknown    = "unknown"
+	remotePaused     = "paused"
+)
+
+var (
+	folderErrorMessages            int
+	warnCompletion, critCompletion float64
+	warnNeedBytes, critNeedBytes   uint64
+	warnNeedItems, critNeedItems   int
+	folderOverrides                map[string]completionThresholds
+	deviceOverrides                map[string]completionThresholds
+
+	foldersStateFile               string
+	warnOutOfSync, critOutOfSync   time.Duration
+	warnNoProgress, critNoProgress time.Duration
+
+	foldersPerfData string
+
+	offlineStatus int
+	offlineGrace  time.Duration
+
+	notSharingStatus, unknownStatus, pausedStatus int
+)
 
 var foldersCmd = cobra.Command{
 	Use:   "folders",
 	Short: "Check status of syncthing folders",
 	Long: `Check status of syncthing folders.
 
-Checks for any folder error and completion status of all clients.`,
+Checks for any folder error and completion status of all clients.
+
+It fetches all errors of every folder and outputs number of errors per folder
+as performance data. Errors of a folder are grouped by error message and only
+first --error-messages distinct messages are outputted.
+
+It outputs warning or critical status for devices with completion of a folder
+below --warn or --crit percentage, or with number of needed bytes or items
+not less than given thresholds. Zero threshold disables it. Completion
+thresholds can be overridden per folder ID or label with --folder-threshold,
+and per device short ID or name with --device-threshold, like nas=90:50.
+Device thresholds win over folder thresholds.
+
+With --state-file it remembers when every device went out of sync and when
+its completion changed last time. Out of sync devices have warning or critical
+status, if they are out of sync longer than --warn-out-of-sync or
+--crit-out-of-sync, or there is no progress longer than --warn-no-progress or
+--crit-no-progress. The worst of it and status by completion thresholds wins,
+so --warn 0 warns only about devices out of sync for long. These thresholds
+require --state-file.
+
+Devices, which aren't connected, are always outputted as offline, with their
+last seen time and status given by --offline. Its default status is "ok",
+because the seen check alerts about them already. Completion of offline devices
+is stale, so they aren't out of sync, until they are offline longer than
+--offline-grace. Zero --offline-grace means never. Syncthing reports unknown
+state of folders on offline devices.
+
+Remote devices, which don't share a folder back, have unknown state of it, or
+paused it, aren't out of sync. They are outputted with status given by
+--remote-not-sharing, --remote-unknown and --remote-paused instead, unless they
+are offline. Status "ok" disables it.
+
+Outputs completion percentage, needed bytes, items and deletions of every
+folder and device pair, and their totals, as performance data. With
+--perf-data total it outputs totals only.`,
+
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if folderErrorMessages < 0 {
+			return fmt.Errorf("--error-messages %v is negative",
+				folderErrorMessages)
+		} else if err := validCompletion(warnCompletion); err != nil {
+			return fmt.Errorf("--warn: %w", err)
+		} else if err := validCompletion(critCompletion); err != nil {
+			return fmt.Errorf("--crit: %w", err)
+		} else if warnNeedItems < 0 {
+			return fmt.Errorf("--warn-need-items %v is negative", warnNeedItems)
+		} else if critNeedItems < 0 {
+			return fmt.Errorf("--crit-need-items %v is negative", critNeedItems)
+		} else if foldersStateFile == "" && (warnOutOfSync != 0 ||
+			critOutOfSync != 0 || warnNoProgress != 0 || critNoProgress != 0) {
+			return errors.New("--warn-out-of-sync, --crit-out-of-sync, " +
+				"--warn-no-progress and --crit-no-progress " +
+				"require --state-file")
+		}
+
+		switch foldersPerfData {
+		case perfDataPairs, perfDataTotal:
+		default:
+			return fmt.Errorf("--perf-data %q not one of pairs or total",
+				foldersPerfData)
+		}
+		return nil
+	},
 
 	Run: func(cmd *cobra.Command, args []string) {
-		NewFoldersCheck(mustAPIClient()).WithExcludeDevices(excludeDevices).
-			Run().Response().OutputAndExit()
+		ctx, cancel := checkContext(cmd)
+		defer cancel()
+		NewFoldersCheck(mustAPIClient()).
+			WithExcludeDevices(excludeDevices).
+			WithErrorMessages(folderErrorMessages).
+			WithCompletionThresholds(warnCompletion, critCompletion).
+			WithNeedThresholds(warnNeedBytes, critNeedBytes, warnNeedItems,
+				critNeedItems).
+			WithFolderThresholds(folderOverrides).
+			WithDeviceThresholds(deviceOverrides).
+			WithStateFile(foldersStateFile).
+			WithOutOfSyncThresholds(warnOutOfSync, critOutOfSync).
+			WithNoProgressThresholds(warnNoProgress, critNoProgress).
+			WithPerfData(foldersPerfData).
+			WithOffline(offlineStatus, offlineGrace).
+			WithRemoteStates(notSharingStatus, unknownStatus, pausedStatus).
+			Run(ctx).Response().OutputAndExit()
 	},
 }
 
+func init() {
+	foldersCmd.Flags().IntVar(&folderErrorMessages, "error-messages", 5,
+		"output that many distinct error messages of every folder")
+	foldersCmd.Flags().Float64VarP(&warnCompletion, "warn", "w", 100,
+		"warning if completion percentage is below that")
+	foldersCmd.Flags().Float64VarP(&critCompletion, "crit", "c", 0,
+		"critical if completion percentage is below that")
+	foldersCmd.Flags().Var(newSizeValue(0, &warnNeedBytes), "warn-need-bytes",
+		"warning threshold of needed bytes")
+	foldersCmd.Flags().Var(newSizeValue(0, &critNeedBytes), "crit-need-bytes",
+		"critical threshold of needed bytes")
+	foldersCmd.Flags().IntVar(&warnNeedItems, "warn-need-items", 0,
+		"warning threshold of needed items")
+	foldersCmd.Flags().IntVar(&critNeedItems, "crit-need-items", 0,
+		"critical threshold of needed items")
+	foldersCmd.Flags().Var(newOverridesValue(&folderOverrides),
+		"folder-threshold", "completion thresholds of folder, like ID=WARN[:CRIT]")
+	foldersCmd.Flags().Var(newOverridesValue(&deviceOverrides),
+		"device-threshold", "completion thresholds of device, like ID=WARN[:CRIT]")
+
+	foldersCmd.Flags().StringVar(&foldersStateFile, "state-file", "",
+		"file with out of sync state of devices")
+	foldersCmd.Flags().DurationVar(&warnOutOfSync, "warn-out-of-sync", 0,
+		"warning if device is out of sync longer than that")
+	foldersCmd.Flags().DurationVar(&critOutOfSync, "crit-out-of-sync", 0,
+		"critical if device is out of sync longer than that")
+	foldersCmd.Flags().DurationVar(&warnNoProgress, "warn-no-progress", 0,
+		"warning if out of sync device has no progress longer than that")
+	foldersCmd.Flags().DurationVar(&critNoProgress, "crit-no-progress", 0,
+		"critical if out of sync device has no progress longer than that")
+
+	foldersCmd.Flags().StringVar(&foldersPerfData, "perf-data", perfDataPairs,
+		"performance data of completions: pairs or total")
+
+	foldersCmd.Flags().Var(
+		newStatusValue(monitoringplugin.OK, &offlineStatus), "offline",
+		"status of offline devices")
+	foldersCmd.Flags().DurationVar(&offlineGrace, "offline-grace", 24*time.Hour,
+		"offline devices are out of sync, if offline longer than that")
+
+	foldersCmd.Flags().Var(
+		newStatusValue(monitoringplugin.WARNING, &notSharingStatus),
+		"remote-not-sharing", "status of remote devices not sharing folder back")
+	foldersCmd.Flags().Var(
+		newStatusValue(monitoringplugin.WARNING, &unknownStatus),
+		"remote-unknown", "status of remote devices with unknown state of folder")
+	foldersCmd.Flags().Var(
+		newStatusValue(monitoringplugin.OK, &pausedStatus),
+		"remote-paused", "status of remote devices, which paused folder")
+}
+
 func NewFoldersCheck(apiClient *client.Client) *FoldersCheck {
-	c := &FoldersCheck{client: apiClient}
+	c := &FoldersCheck{
+		client:        apiClient,
+		errorMessages: folderErrorMessages,
+
+		minCompletion:   completionThresholds{warnCompletion, critCompletion},
+		warnNeedBytes:   warnNeedBytes,
+		critNeedBytes:   critNeedBytes,
+		warnNeedItems:   warnNeedItems,
+		critNeedItems:   critNeedItems,
+		folderOverrides: folderOverrides,
+		deviceOverrides: deviceOverrides,
+
+		stateFile:      foldersStateFile,
+		warnOutOfSync:  warnOutOfSync,
+		critOutOfSync:  critOutOfSync,
+		warnNoProgress: warnNoProgress,
+		critNoProgress: critNoProgress,
+
+		perfData: foldersPerfData,
+
+		offlineStatus: offlineStatus,
+		offlineGrace:  offlineGrace,
+
+		notSharingStatus: notSharingStatus,
+		unknownStatus:    unknownStatus,
+		pausedStatus:     pausedStatus,
+	}
 	return c.applyOptions()
 }
 
@@ -39,11 +230,33 @@ type FoldersCheck struct {
 	resp   *monitoringplugin.Response
 
 	excludeDevices lookupDeviceId
+	errorMessages  int
+
+	minCompletion                completionThresholds
+	warnNeedBytes, critNeedBytes uint64
+	warnNeedItems, critNeedItems int
+	folderOverrides              map[string]completionThresholds
+	deviceOverrides              map[string]completionThresholds
+
+	stateFile                      string
+	warnOutOfSync, critOutOfSync   time.Duration
+	warnNoProgress, critNoProgress time.Duration
+
+	perfData string
+
+	offlineStatus int
+	offlineGrace  time.Duration
+
+	notSharingStatus, unknownStatus, pausedStatus int
 
 	devices      map[string]api.DeviceConfiguration
 	folders      []api.FolderConfiguration
-	folderErrors []api.FileError
+	conns        *api.Connections
+	stats        map[string]api.DeviceStatistics
+	folderErrors []*folderErrors
 	completions  []*api.FolderCompletion
+	syncState    *client.SyncState
+	syncPairs    []*client.SyncPair
 }
 
 func (self *FoldersCheck) applyOptions() *FoldersCheck {
@@ -58,24 +271,136 @@ func (self *FoldersCheck) WithExcludeDevices(devices []string) *FoldersCheck {
 	return self
 }
 
+func (self *FoldersCheck) WithErrorMessages(n int) *FoldersCheck {
+	self.errorMessages = n
+	return self
+}
+
+// WithCompletionThresholds configures warning and critical thresholds of
+// completion percentage.
+func (self *FoldersCheck) WithCompletionThresholds(warn, crit float64,
+) *FoldersCheck {
+	self.minCompletion = completionThresholds{warn: warn, crit: crit}
+	return self
+}
+
+// WithNeedThresholds configures warning and critical thresholds of needed bytes
+// and items.
+func (self *FoldersCheck) WithNeedThresholds(warnBytes, critBytes uint64,
+	warnItems, critItems int,
+) *FoldersCheck {
+	self.warnNeedBytes, self.critNeedBytes = warnBytes, critBytes
+	self.warnNeedItems, self.critNeedItems = warnItems, critItems
+	return self
+}
+
+// WithFolderThresholds configures completion thresholds by folder ID or label.
+func (self *FoldersCheck) WithFolderThresholds(
+	overrides map[string]completionThresholds,
+) *FoldersCheck {
+	self.folderOverrides = overrides
+	return self
+}
+
+// WithDeviceThresholds configures completion thresholds by device short ID or
+// name. They win over thresholds of folders.
+func (self *FoldersCheck) WithDeviceThresholds(
+	overrides map[string]completionThresholds,
+) *FoldersCheck {
+	self.deviceOverrides = overrides
+	return self
+}
+
+// WithStateFile configures state file, which remembers out of sync devices
+// between runs. Empty fname disables it.
+func (self *FoldersCheck) WithStateFile(fname string) *FoldersCheck {
+	self.stateFile = fname
+	return self
+}
+
+// WithOutOfSyncThresholds configures how long devices can be out of sync. It
+// requires state file.
+func (self *FoldersCheck) WithOutOfSyncThresholds(warn, crit time.Duration,
+) *FoldersCheck {
+	self.warnOutOfSync, self.critOutOfSync = warn, crit
+	return self
+}
+
+// WithNoProgressThresholds configures how long out of sync devices can be
+// without progress. It requires state file.
+func (self *FoldersCheck) WithNoProgressThresholds(warn, crit time.Duration,
+) *FoldersCheck {
+	self.warnNoProgress, self.critNoProgress = warn, crit
+	return self
+}
+
+// WithPerfData configures performance data of completions: [perfDataPairs]
+// outputs every folder and device pair with totals, [perfDataTotal] outputs
+// totals only.
+func (self *FoldersCheck) WithPerfData(kind string) *FoldersCheck {
+	self.perfData = kind
+	return self
+}
+
+// WithOffline configures status of offline devices and how long they can be
+// offline, before they are out of sync. Offline devices are outputted with any
+// status. Zero grace means offline devices are never out of sync.
+func (self *FoldersCheck) WithOffline(status int, grace time.Duration,
+) *FoldersCheck {
+	self.offlineStatus, self.offlineGrace = status, grace
+	return self
+}
+
+// WithRemoteStates configures status of remote devices, which don't share a
+// folder back, have unknown state of it, or paused it. OK status disables
+// output of them.
+func (self *FoldersCheck) WithRemoteStates(notSharing, unknown, paused int,
+) *FoldersCheck {
+	self.notSharingStatus = notSharing
+	self.unknownStatus = unknown
+	self.pausedStatus = paused
+	return self
+}
+
 func (self *FoldersCheck) Response() *monitoringplugin.Response {
 	return self.resp
 }
 
-func (self *FoldersCheck) Run() *FoldersCheck {
-	if !self.fetch(context.Background()) {
+func (self *FoldersCheck) Run(ctx context.Context) *FoldersCheck {
+	defer outputRetries(self.resp, self.client)
+
+	if !self.loadSyncState() || !self.fetch(ctx) {
+		return self
+	} else if self.syncState != nil && !self.updateSyncState() {
 		return self
 	}
 
 	self.resp.WithDefaultOkMessage(strconv.Itoa(len(self.folders)) + foldersOkMsg)
+	self.outputErrorsPerf()
+	self.outputCompletionsPerf()
 	if self.checkFolderErrors() {
 		self.checkCompletions()
+		self.checkOffline()
+		self.checkRemoteStates()
 		self.checkNotShared()
 	}
 	self.outputExcluded()
 	return self
 }
 
+func (self *FoldersCheck) loadSyncState() bool {
+	if self.stateFile == "" {
+		return true
+	}
+
+	syncState, err := client.LoadSyncState(self.stateFile)
+	if self.resp.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "", true) {
+		return false
+	}
+	self.syncState = syncState
+	return true
+}
+
 func (self *FoldersCheck) fetch(parentCtx context.Context) bool {
 	if !self.fetchDevicesFolders(parentCtx) {
 		return false
@@ -86,9 +411,7 @@ func (self *FoldersCheck) fetch(parentCtx context.Context) bool {
 
 	self.fetchFolderErrors(ctx, g)
 	self.fetchCompletions(ctx, g)
-
-	self.resp.UpdateStatusOnError(g.Wait(), monitoringplugin.CRITICAL, "", true)
-	return self.resp.GetStatusCode() == monitoringplugin.OK
+	return checkFetchError(parentCtx, self.resp, g.Wait())
 }
 
 func (self *FoldersCheck) fetchDevicesFolders(parentCtx context.Context) bool {
@@ -97,15 +420,16 @@ func (self *FoldersCheck) fetchDevicesFolders(parentCtx context.Context) bool {
 
 	g.Go(func() error { return self.fetchDevices(ctx) })
 	g.Go(func() error { return self.fetchFolders(ctx) })
+	g.Go(func() error { return self.fetchConns(ctx) })
+	g.Go(func() error { return self.fetchStats(ctx) })
 
-	self.resp.UpdateStatusOnError(g.Wait(), monitoringplugin.CRITICAL, "", true)
-	return self.resp.GetStatusCode() == monitoringplugin.OK
+	return checkFetched(parentCtx, self.resp, g.Wait())
 }
 
 func (self *FoldersCheck) fetchDevices(ctx context.Context) error {
 	devices, err := self.client.Devices(ctx)
 	if err != nil {
-		return err
+		return newFetchError("devices", err)
 	}
 
 	self.devices = make(map[string]api.DeviceConfiguration, len(devices))
@@ -119,38 +443,59 @@ func (self *FoldersCheck) fetchDevices(ctx context.Context) error {
 func (self *FoldersCheck) fetchFolders(ctx context.Context) error {
 	folders, err := self.client.Folders(ctx)
 	if err != nil {
-		return err
+		return newFetchError("folders", err)
 	}
 	self.folders = folders
 	return nil
 }
 
+func (self *FoldersCheck) fetchConns(ctx context.Context) error {
+	conns, err := self.client.Connections(ctx)
+	if err != nil {
+		return newFetchError("connections", err)
+	}
+	self.conns = conns
+	return nil
+}
+
+func (self *FoldersCheck) fetchStats(ctx context.Context) error {
+	stats, err := self.client.DeviceStats(ctx)
+	if err != nil {
+		return newFetchError("device stats", err)
+	}
+	self.stats = stats
+	return nil
+}
+
 func (self *FoldersCheck) fetchFolderErrors(ctx context.Context,
 	g *errgroup.Group,
 ) {
-	self.folderErrors = make([]api.FileError, len(self.folders))
+	self.folderErrors = make([]*folderErrors, len(self.folders))
 	for i := range self.folders {
 		if ctx.Err() != nil {
 			break
 		}
 		folder := &self.folders[i]
 		g.Go(func() error {
-			folderError, err := self.folderError(ctx, folder.Id)
-			self.folderErrors[i] = folderError
+			folderErrors, err := self.folderError(ctx, folder.Id)
+			self.folderErrors[i] = folderErrors
 			return err
 		})
 	}
 }
 
 func (self *FoldersCheck) folderError(ctx context.Context, folderId string,
-) (folderError api.FileError, err error) {
-	fileErrors, err := self.client.FolderErrors(ctx, folderId)
-	if err != nil {
-		err = fmt.Errorf("folder id=%q: %w", folderId, err)
-	} else if len(fileErrors) > 0 {
-		folderError = fileErrors[len(fileErrors)-1]
+) (*folderErrors, error) {
+	var folderErrors folderErrors
+	fileErrors := self.client.AllFolderErrors(ctx, folderId, folderErrorsPerPage)
+	for fileErr, err := range fileErrors {
+		if err != nil {
+			return nil, newFetchError("errors of folder "+folderId,
+				fmt.Errorf("folder id=%q: %w", folderId, err))
+		}
+		folderErrors.Add(fileErr)
 	}
-	return folderError, err
+	return &folderErrors, nil
 }
 
 func (self *FoldersCheck) fetchCompletions(ctx context.Context,
@@ -192,8 +537,9 @@ func (self *FoldersCheck) completion(ctx context.Context,
 ) (*api.FolderCompletion, error) {
 	comp, err := self.client.Completion(ctx, folder.Id, deviceId)
 	if err != nil {
-		err = fmt.Errorf("completion folder=%q, device=%q: %w",
-			folderName(folder), self.deviceName(deviceId), err)
+		err = newFetchError("completion of folder "+folder.Id,
+			fmt.Errorf("completion folder=%q, device=%q: %w",
+				folderName(folder), self.deviceName(deviceId), err))
 	}
 	return comp, err
 }
@@ -202,10 +548,107 @@ func (self *FoldersCheck) deviceName(id string) string {
 	return deviceName(id, self.devices[id].Name)
 }
 
+// updateSyncState updates state of every out of sync device and saves it.
+// State of devices without fetched completion, like after timeout, is kept
+// unchanged.
+func (self *FoldersCheck) updateSyncState() bool {
+	now := time.Now()
+	self.syncPairs = make([]*client.SyncPair, len(self.completions))
+	var idx int
+	for i := range self.folders {
+		folder := &self.folders[i]
+		for j := range folder.Devices {
+			deviceId := folder.Devices[j].DeviceId
+			if comp := self.completions[idx+j]; comp != nil {
+				self.syncPairs[idx+j] = self.syncState.Update(folder.Id, deviceId,
+					comp, now)
+			} else if !self.excludeDevices.Has(deviceId) {
+				self.syncState.Keep(folder.Id, deviceId)
+			}
+		}
+		idx += len(folder.Devices)
+	}
+
+	err := self.syncState.Save()
+	return !self.resp.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "",
+		true)
+}
+
+func (self *FoldersCheck) outputErrorsPerf() {
+	for i := range self.folders {
+		folderErrors := self.folderErrors[i]
+		if folderErrors == nil {
+			continue
+		}
+		point := monitoringplugin.NewPerformanceDataPoint("errors",
+			folderErrors.Total()).SetLabel(perfLabel(self.folders[i].Id))
+		if err := self.resp.AddPerformanceDataPoint(point); err != nil {
+			self.resp.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "", true)
+		}
+	}
+}
+
+func (self *FoldersCheck) outputCompletionsPerf() {
+	var total api.FolderCompletion
+	var idx int
+	for i := range self.folders {
+		folder := &self.folders[i]
+		for j := range folder.Devices {
+			comp := self.completions[idx+j]
+			if comp == nil {
+				continue
+			}
+			total.GlobalBytes += comp.GlobalBytes
+			total.NeedBytes += comp.NeedBytes
+			total.NeedItems += comp.NeedItems
+			total.NeedDeletes += comp.NeedDeletes
+			if self.perfData != perfDataTotal {
+				self.addCompletionPerf(comp, perfLabel(folder.Id+"_"+
+					newDeviceId(folder.Devices[j].DeviceId).Short()))
+			}
+		}
+		idx += len(folder.Devices)
+	}
+
+	total.Completion = 100
+	if total.GlobalBytes > 0 {
+		total.Completion = math.Round(100*(1-float64(total.NeedBytes)/
+			float64(total.GlobalBytes))*100) / 100
+	}
+	self.addCompletionPerf(&total, "")
+}
+
+func (self *FoldersCheck) addCompletionPerf(comp *api.FolderCompletion,
+	label string,
+) {
+	completion := monitoringplugin.NewPerformanceDataPoint("completion",
+		comp.Completion).SetUnit("%").SetLabel(label)
+	if err := self.resp.AddPerformanceDataPoint(completion); err != nil {
+		self.resp.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "", true)
+	}
+
+	needBytes := monitoringplugin.NewPerformanceDataPoint("need_bytes",
+		comp.NeedBytes).SetUnit("B").SetLabel(label)
+	if err := self.resp.AddPerformanceDataPoint(needBytes); err != nil {
+		self.resp.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "", true)
+	}
+
+	for _, point := range [...]*monitoringplugin.PerformanceDataPoint[int]{
+		monitoringplugin.NewPerformanceDataPoint("need_items", comp.NeedItems),
+		monitoringplugin.NewPerformanceDataPoint("need_deletes",
+			comp.NeedDeletes),
+	} {
+		point.SetLabel(label)
+		if err := self.resp.AddPerformanceDataPoint(point); err != nil {
+			self.resp.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "", true)
+		}
+	}
+}
+
 func (self *FoldersCheck) checkFolderErrors() bool {
 	var numErrors int
-	for i := range self.folderErrors {
-		if self.folderErrors[i].Error != "" {
+	for _, folderErrors := range self.folderErrors {
+		if folderErrors != nil && folderErrors.Total() > 0 {
 			numErrors++
 		}
 	}
@@ -216,24 +659,49 @@ func (self *FoldersCheck) checkFolderErrors() bool {
 	self.resp.UpdateStatus(monitoringplugin.WARNING, fmt.Sprintf(
 		"%v/%v folders with errors", numErrors, len(self.folders)))
 	for i := range self.folders {
-		self.outputFolderError(&self.folders[i], &self.folderErrors[i])
+		if folderErrors := self.folderErrors[i]; folderErrors != nil {
+			self.outputFolderErrors(&self.folders[i], folderErrors)
+		}
 	}
 	return false
 }
 
-func (self *FoldersCheck) outputFolderError(folder *api.FolderConfiguration,
-	folderError *api.FileError,
+func (self *FoldersCheck) outputFolderErrors(folder *api.FolderConfiguration,
+	folderErrors *folderErrors,
 ) {
-	if folderError.Error == "" {
+	switch folderErrors.Total() {
+	case 0:
+		return
+	case 1:
+		folderError := &folderErrors.groups[0]
+		self.resp.UpdateStatus(monitoringplugin.WARNING,
+			"folder: "+folderName(folder))
+		self.resp.UpdateStatus(monitoringplugin.WARNING,
+			"path: "+folderError.path)
+		self.resp.UpdateStatus(monitoringplugin.WARNING,
+			"error: "+folderError.err)
 		return
 	}
 
 	self.resp.UpdateStatus(monitoringplugin.WARNING,
 		"folder: "+folderName(folder))
 	self.resp.UpdateStatus(monitoringplugin.WARNING,
-		"path: "+folderError.Path)
-	self.resp.UpdateStatus(monitoringplugin.WARNING,
-		"error: "+folderError.Error)
+		"errors: "+strconv.Itoa(folderErrors.Total()))
+
+	groups := folderErrors.groups
+	if len(groups) > self.errorMessages {
+		groups = groups[:self.errorMessages]
+	}
+	for i := range groups {
+		g := &groups[i]
+		self.resp.UpdateStatus(monitoringplugin.WARNING, fmt.Sprintf(
+			"error: %v (%v files, first: %v)", g.err, g.count, g.path))
+	}
+
+	if more := len(folderErrors.groups) - len(groups); more > 0 {
+		self.resp.UpdateStatus(monitoringplugin.WARNING, fmt.Sprintf(
+			"and %v more distinct errors", more))
+	}
 }
 
 func folderName(folder *api.FolderConfiguration) string {
@@ -246,24 +714,39 @@ func (self *FoldersCheck) checkCompletions() {
 		return
 	}
 
-	self.resp.UpdateStatus(monitoringplugin.WARNING, fmt.Sprintf(
+	worst := monitoringplugin.OK
+	for i := range outOfSync {
+		worst = max(worst, outOfSync[i].status)
+	}
+
+	self.resp.UpdateStatus(worst, fmt.Sprintf(
 		"%v/%v folders out of sync", foldersCnt, len(self.folders)))
 	for i := range self.folders {
-		if devices := outOfSync[i]; devices != "" {
+		if devices := &outOfSync[i]; devices.status != monitoringplugin.OK {
 			self.outputOutOfSync(&self.folders[i], devices)
 		}
 	}
 }
 
-func (self *FoldersCheck) outOfSyncFolders() (int, []string) {
-	outOfSync := make([]string, len(self.folders))
+// outOfSync is a list of out of sync devices of a folder, with the worst
+// status of them.
+type outOfSync struct {
+	devices []string
+	status  int
+}
+
+func (self *FoldersCheck) outOfSyncFolders() (int, []outOfSync) {
+	outOfSync := make([]outOfSync, len(self.folders))
 	var idx, cnt int
 	for i := range self.folders {
 		folder := &self.folders[i]
-		syncDevices := self.outOfSyncDevices(folder.Devices,
-			self.completions[idx:idx+len(folder.Devices)])
-		if len(syncDevices) > 0 {
-			outOfSync[i] = strings.Join(syncDevices, ", ")
+		var syncPairs []*client.SyncPair
+		if self.syncPairs != nil {
+			syncPairs = self.syncPairs[idx : idx+len(folder.Devices)]
+		}
+		outOfSync[i] = self.outOfSyncDevices(folder,
+			self.completions[idx:idx+len(folder.Devices)], syncPairs)
+		if outOfSync[i].status != monitoringplugin.OK {
 			cnt++
 		}
 		idx += len(folder.Devices)
@@ -271,28 +754,233 @@ func (self *FoldersCheck) outOfSyncFolders() (int, []string) {
 	return cnt, outOfSync
 }
 
-func (self *FoldersCheck) outOfSyncDevices(
-	devices []api.FolderDeviceConfiguration, completions []*api.FolderCompletion,
-) []string {
-	outOfSync := make([]string, 0, len(devices))
-	for i := range devices {
+func (self *FoldersCheck) outOfSyncDevices(folder *api.FolderConfiguration,
+	completions []*api.FolderCompletion, syncPairs []*client.SyncPair,
+) outOfSync {
+	result := outOfSync{devices: make([]string, 0, len(folder.Devices))}
+	for i := range folder.Devices {
 		comp := completions[i]
-		if comp != nil {
-			if pct := int(comp.Completion); pct < 100 {
-				outOfSync = append(outOfSync, self.deviceName(devices[i].DeviceId)+
-					" - "+strconv.Itoa(pct)+"%")
-			}
+		if comp == nil {
+			continue
+		}
+
+		deviceId := folder.Devices[i].DeviceId
+		if self.offlineInGrace(deviceId) {
+			continue
+		} else if remoteInvalid(comp) && !self.offlineUnknown(deviceId, comp) {
+			continue
+		}
+
+		status := self.completionStatus(folder, deviceId, comp)
+		var pair *client.SyncPair
+		if syncPairs != nil {
+			pair = syncPairs[i]
+		}
+		if pair != nil {
+			status = max(status, self.durationStatus(pair))
+		}
+		if status == monitoringplugin.OK {
+			continue
+		}
+
+		msg := self.deviceName(deviceId) + " - " +
+			strconv.Itoa(int(comp.Completion)) + "%"
+		if pair != nil {
+			msg += ", out of sync " + sinceString(pair.OutOfSyncSince) +
+				", no progress " + sinceString(pair.ProgressTime)
+		}
+		result.devices = append(result.devices, msg)
+		result.status = max(result.status, status)
+	}
+	return result
+}
+
+// durationStatus returns status of out of sync pair by how long it's out of
+// sync and without progress. It's OK, if there are no such thresholds.
+func (self *FoldersCheck) durationStatus(pair *client.SyncPair) int {
+	outOfSync := time.Since(pair.OutOfSyncSince)
+	noProgress := time.Since(pair.ProgressTime)
+	switch {
+	case self.critOutOfSync > 0 && outOfSync >= self.critOutOfSync,
+		self.critNoProgress > 0 && noProgress >= self.critNoProgress:
+		return monitoringplugin.CRITICAL
+	case self.warnOutOfSync > 0 && outOfSync >= self.warnOutOfSync,
+		self.warnNoProgress > 0 && noProgress >= self.warnNoProgress:
+		return monitoringplugin.WARNING
+	}
+	return monitoringplugin.OK
+}
+
+func sinceString(t time.Time) string {
+	return time.Since(t).Truncate(time.Second).String()
+}
+
+func (self *FoldersCheck) completionStatus(folder *api.FolderConfiguration,
+	deviceId string, comp *api.FolderCompletion,
+) int {
+	status := self.thresholds(folder, deviceId).Status(comp.Completion)
+	switch {
+	case self.critNeedBytes > 0 && uint64(comp.NeedBytes) >= self.critNeedBytes,
+		self.critNeedItems > 0 && comp.NeedItems >= self.critNeedItems:
+		status = monitoringplugin.CRITICAL
+	case self.warnNeedBytes > 0 && uint64(comp.NeedBytes) >= self.warnNeedBytes,
+		self.warnNeedItems > 0 && comp.NeedItems >= self.warnNeedItems:
+		status = max(status, monitoringplugin.WARNING)
+	}
+	return status
+}
+
+// thresholds returns completion thresholds of folder on device. Thresholds of
+// device win over thresholds of folder.
+func (self *FoldersCheck) thresholds(folder *api.FolderConfiguration,
+	deviceId string,
+) completionThresholds {
+	shortId := newDeviceId(deviceId).Short()
+	for _, key := range [...]string{shortId, self.devices[deviceId].Name} {
+		if t, ok := self.deviceOverrides[key]; ok {
+			return t
 		}
 	}
-	return outOfSync
+
+	for _, key := range [...]string{folder.Id, folder.Label} {
+		if t, ok := self.folderOverrides[key]; ok {
+			return t
+		}
+	}
+	return self.minCompletion
 }
 
 func (self *FoldersCheck) outputOutOfSync(folder *api.FolderConfiguration,
-	devices string,
+	outOfSync *outOfSync,
 ) {
-	self.resp.UpdateStatus(monitoringplugin.WARNING,
-		"folder: "+folderName(folder))
-	self.resp.UpdateStatus(monitoringplugin.WARNING, "device: "+devices)
+	self.resp.UpdateStatus(outOfSync.status, "folder: "+folderName(folder))
+	self.resp.UpdateStatus(outOfSync.status,
+		"device: "+strings.Join(outOfSync.devices, ", "))
+}
+
+// offline returns true, if device isn't connected. Paused devices aren't
+// offline.
+func (self *FoldersCheck) offline(deviceId string) bool {
+	conn, ok := self.conns.Connections[deviceId]
+	return ok && !conn.Connected && !conn.Paused
+}
+
+// offlineInGrace returns true, if device is offline no longer than grace
+// period, so its completion is stale and it isn't out of sync.
+func (self *FoldersCheck) offlineInGrace(deviceId string) bool {
+	if !self.offline(deviceId) {
+		return false
+	} else if self.offlineGrace == 0 {
+		return true
+	}
+
+	lastSeen := self.stats[deviceId].LastSeen
+	return !neverSeen(lastSeen) && time.Since(lastSeen) < self.offlineGrace
+}
+
+// offlineUnknown returns true, if remote state of folder is unknown, because
+// device is offline. Syncthing reports offline devices like this.
+func (self *FoldersCheck) offlineUnknown(deviceId string,
+	comp *api.FolderCompletion,
+) bool {
+	return comp.RemoteState == remoteUnknown && self.offline(deviceId)
+}
+
+func neverSeen(t time.Time) bool { return t.IsZero() || t.Unix() == 0 }
+
+func (self *FoldersCheck) checkOffline() {
+	offline := self.offlineDevices()
+	if len(offline) == 0 {
+		return
+	}
+
+	self.resp.UpdateStatus(self.offlineStatus,
+		strconv.Itoa(len(offline))+" devices offline")
+	for _, deviceId := range offline {
+		msg := "device: " + self.deviceName(deviceId) + " - "
+		if lastSeen := self.stats[deviceId].LastSeen; neverSeen(lastSeen) {
+			msg += "never seen"
+		} else {
+			msg += "last seen " + sinceString(lastSeen) + " ago"
+		}
+		self.resp.UpdateStatus(self.offlineStatus, msg)
+	}
+}
+
+// offlineDevices returns IDs of not excluded offline devices, which share any
+// folder, in order of first occurrence.
+func (self *FoldersCheck) offlineDevices() []string {
+	var offline []string
+	seen := make(map[string]struct{})
+	for i := range self.folders {
+		folder := &self.folders[i]
+		for j := range folder.Devices {
+			deviceId := folder.Devices[j].DeviceId
+			if _, ok := seen[deviceId]; ok {
+				continue
+			}
+			seen[deviceId] = struct{}{}
+			if !self.excludeDevices.Has(deviceId) && self.offline(deviceId) {
+				offline = append(offline, deviceId)
+			}
+		}
+	}
+	return offline
+}
+
+// remoteInvalid returns true, if remote device doesn't share the folder back,
+// has unknown state of it, or paused it, so its completion is meaningless.
+func remoteInvalid(comp *api.FolderCompletion) bool {
+	switch comp.RemoteState {
+	case remoteNotSharing, remoteUnknown, remotePaused:
+		return true
+	}
+	return false
+}
+
+func (self *FoldersCheck) checkRemoteStates() {
+	remotes := [...]struct {
+		state  string
+		status int
+		msg    string
+	}{
+		{remoteNotSharing, self.notSharingStatus, " folders not shared back: "},
+		{remoteUnknown, self.unknownStatus, " folders in unknown state: "},
+		{remotePaused, self.pausedStatus, " folders paused remotely: "},
+	}
+
+	for i := range remotes {
+		r := &remotes[i]
+		if r.status == monitoringplugin.OK {
+			continue
+		}
+		if pairs := self.remoteState(r.state); len(pairs) > 0 {
+			self.resp.UpdateStatus(r.status, strconv.Itoa(len(pairs))+r.msg+
+				strings.Join(pairs, ", "))
+		}
+	}
+}
+
+// remoteState returns folder and device pairs with remote state of folder,
+// like "folder on device". Offline devices are skipped, because they are
+// outputted as offline.
+func (self *FoldersCheck) remoteState(state string) []string {
+	var pairs []string
+	var idx int
+	for i := range self.folders {
+		folder := &self.folders[i]
+		for j := range folder.Devices {
+			comp := self.completions[idx+j]
+			deviceId := folder.Devices[j].DeviceId
+			if comp != nil && comp.RemoteState == state &&
+				!self.offline(deviceId) {
+				pairs = append(pairs, folderName(folder)+" on "+
+					self.deviceName(deviceId))
+			}
+		}
+		idx += len(folder.Devices)
+	}
+	return pairs
 }
 
 func (self *FoldersCheck) checkNotShared() {
@@ -317,3 +1005,38 @@ func (self *FoldersCheck) outputExcluded() {
 			"excluded: "+self.excludeDevices.ExcludedString(self.devices))
 	}
 }
+
+// --------------------------------------------------
+
+// folderErrors counts all errors of a folder and groups them by error message,
+// in order of first occurrence.
+type folderErrors struct {
+	total  int
+	groups []folderErrorGroup
+	lookup map[string]int
+}
+
+type folderErrorGroup struct {
+	err   string
+	path  string // path of first file with this error
+	count int
+}
+
+func (self *folderErrors) Add(fileErr *api.FileError) {
+	self.total++
+	if i, ok := self.lookup[fileErr.Error]; ok {
+		self.groups[i].count++
+		return
+	} else if self.lookup == nil {
+		self.lookup = make(map[string]int)
+	}
+
+	self.lookup[fileErr.Error] = len(self.groups)
+	self.groups = append(self.groups, folderErrorGroup{
+		err:   fileErr.Error,
+		path:  fileErr.Path,
+		count: 1,
+	})
+}
+
+func (self *folderErrors) Total() int { return self.total }
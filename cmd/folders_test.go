@@ -1,12 +1,16 @@
 package cmd
 
 import (
+	"fmt"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/dsh2dsh/go-monitoringplugin/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/dsh2dsh/check_syncthing/client"
 	"github.com/dsh2dsh/check_syncthing/client/api"
 )
 
@@ -36,6 +40,90 @@ func TestFoldersCheck_WithExcludeDevices(t *testing.T) {
 	assert.Same(t, check, check.WithExcludeDevices([]string{"A", "B"}))
 }
 
+func TestFoldersCmd_PreRunE(t *testing.T) {
+	origMessages, origPerfData := folderErrorMessages, foldersPerfData
+	t.Cleanup(func() {
+		folderErrorMessages, foldersPerfData = origMessages, origPerfData
+	})
+
+	folderErrorMessages, foldersPerfData = 5, perfDataPairs
+	require.NoError(t, foldersCmd.PreRunE(&foldersCmd, nil))
+
+	folderErrorMessages = -1
+	require.ErrorContains(t, foldersCmd.PreRunE(&foldersCmd, nil),
+		"--error-messages -1 is negative")
+
+	folderErrorMessages, foldersPerfData = 0, "foobar"
+	require.ErrorContains(t, foldersCmd.PreRunE(&foldersCmd, nil),
+		"--perf-data")
+}
+
+func TestFoldersCmd_PreRunE_stateFile(t *testing.T) {
+	origStateFile, origNoProgress := foldersStateFile, warnNoProgress
+	t.Cleanup(func() {
+		foldersStateFile, warnNoProgress = origStateFile, origNoProgress
+	})
+
+	foldersStateFile, warnNoProgress = "", time.Hour
+	require.ErrorContains(t, foldersCmd.PreRunE(&foldersCmd, nil),
+		"require --state-file")
+
+	foldersStateFile = "sync.json"
+	require.NoError(t, foldersCmd.PreRunE(&foldersCmd, nil))
+}
+
+func TestFoldersCmd_PreRunE_thresholds(t *testing.T) {
+	origWarn, origCrit := warnCompletion, critCompletion
+	origWarnItems, origCritItems := warnNeedItems, critNeedItems
+	t.Cleanup(func() {
+		warnCompletion, critCompletion = origWarn, origCrit
+		warnNeedItems, critNeedItems = origWarnItems, origCritItems
+	})
+
+	tests := []struct {
+		name       string
+		warn, crit float64
+		warnItems  int
+		critItems  int
+		err        string
+	}{
+		{name: "valid", warn: 100, crit: 50, warnItems: 1, critItems: 10},
+		{
+			name: "warn above 100",
+			warn: 150,
+			err:  `--warn: completion "150" out of range 0..100`,
+		},
+		{
+			name: "negative crit",
+			crit: -5,
+			err:  `--crit: completion "-5" out of range 0..100`,
+		},
+		{
+			name:      "negative warn need items",
+			warnItems: -1,
+			err:       "--warn-need-items -1 is negative",
+		},
+		{
+			name:      "negative crit need items",
+			critItems: -1,
+			err:       "--crit-need-items -1 is negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnCompletion, critCompletion = tt.warn, tt.crit
+			warnNeedItems, critNeedItems = tt.warnItems, tt.critItems
+			err := foldersCmd.PreRunE(&foldersCmd, nil)
+			if tt.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
 func TestFoldersCheck_Run(t *testing.T) {
 	const testId2 = "XXXXXX2-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX"
 	const testId4 = "XXXXXX4-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX"
@@ -65,6 +153,16 @@ func TestFoldersCheck_Run(t *testing.T) {
 		Folder: "default",
 	}
 
+	conns := api.Connections{
+		Connections: map[string]api.ConnectionStats{
+			testId2: {Connected: true},
+			testId4: {Connected: true},
+		},
+	}
+	stats := map[string]api.DeviceStatistics{}
+
+	errorsEP := "/rest/folder/errors?folder=default&page=1&perpage=1000"
+	errorsEP2 := "/rest/folder/errors?folder=default2&page=1&perpage=1000"
 	compEP := "/rest/db/completion?device=" + testId2 + "&folder=default"
 	compEP2 := "/rest/db/completion?device=" + testId2 + "&folder=default2"
 	completion := api.FolderCompletion{Completion: 100}
@@ -78,49 +176,77 @@ func TestFoldersCheck_Run(t *testing.T) {
 		{
 			name: "without endpoints",
 			assertOutput: func(t *testing.T, rawOutput string) {
-				assert.Contains(t, rawOutput, "CRITICAL: ")
+				assert.Contains(t, rawOutput, "UNKNOWN: /rest/")
 			},
 		},
 		{
 			name: "with devices",
 			endpoints: map[string]any{
 				"/rest/config/devices": devices,
+				connsEP:                conns,
+				statsEP:                stats,
 			},
 			assertOutput: func(t *testing.T, rawOutput string) {
-				assert.Contains(t, rawOutput, "CRITICAL: ")
+				assert.Contains(t, rawOutput, "UNKNOWN: /rest/")
 			},
 		},
 		{
 			name: "with folders",
 			endpoints: map[string]any{
 				"/rest/config/devices": devices,
+				connsEP:                conns,
+				statsEP:                stats,
 				"/rest/config/folders": folders,
 			},
 			assertOutput: func(t *testing.T, rawOutput string) {
-				assert.Contains(t, rawOutput, "CRITICAL: ")
+				assert.Contains(t, rawOutput, "UNKNOWN: /rest/")
 			},
 		},
 		{
 			name: "with default folder errors",
 			endpoints: map[string]any{
-				"/rest/config/devices":               devices,
-				"/rest/config/folders":               folders,
-				"/rest/folder/errors?folder=default": folderErrors,
+				"/rest/config/devices": devices,
+				connsEP:                conns,
+				statsEP:                stats,
+				"/rest/config/folders": folders,
+				errorsEP:               folderErrors,
 			},
 			assertOutput: func(t *testing.T, rawOutput string) {
-				assert.Contains(t, rawOutput, "CRITICAL: ")
+				assert.Contains(t, rawOutput, "UNKNOWN: /rest/")
 			},
 		},
 		{
 			name: "OK",
 			endpoints: map[string]any{
-				"/rest/config/devices":               devices,
-				"/rest/config/folders":               folders,
-				"/rest/folder/errors?folder=default": folderErrors,
-				compEP:                               &completion,
+				"/rest/config/devices": devices,
+				connsEP:                conns,
+				statsEP:                stats,
+				"/rest/config/folders": folders,
+				errorsEP:               folderErrors,
+				compEP:                 &completion,
+			},
+			assertOutput: func(t *testing.T, rawOutput string) {
+				assert.Equal(t, "OK: 1"+foldersOkMsg+" | 'errors_default'=0"+
+					testCompletionPerf("default_XXXXXX2", &completion)+totalPerf,
+					rawOutput)
+			},
+		},
+		{
+			name: "total perf data",
+			with: func(t *testing.T, check *FoldersCheck) {
+				check.WithPerfData(perfDataTotal)
+			},
+			endpoints: map[string]any{
+				"/rest/config/devices": devices,
+				connsEP:                conns,
+				statsEP:                stats,
+				"/rest/config/folders": folders,
+				errorsEP:               folderErrors,
+				compEP:                 &completion,
 			},
 			assertOutput: func(t *testing.T, rawOutput string) {
-				assert.Equal(t, "OK: 1"+foldersOkMsg, rawOutput)
+				assert.Equal(t, "OK: 1"+foldersOkMsg+" | 'errors_default'=0"+
+					totalPerf, rawOutput)
 			},
 		},
 		{
@@ -130,6 +256,8 @@ func TestFoldersCheck_Run(t *testing.T) {
 			},
 			endpoints: map[string]any{
 				"/rest/config/devices": devices,
+				connsEP:                conns,
+				statsEP:                stats,
 				"/rest/config/folders": []api.FolderConfiguration{
 					{
 						Devices: []api.FolderDeviceConfiguration{
@@ -141,8 +269,8 @@ func TestFoldersCheck_Run(t *testing.T) {
 						Path:  "/Default Folder",
 					},
 				},
-				"/rest/folder/errors?folder=default": folderErrors,
-				compEP:                               &completion,
+				errorsEP: folderErrors,
+				compEP:   &completion,
 			},
 			assertOutput: func(t *testing.T, rawOutput string) {
 				assert.Contains(t, rawOutput, "OK: 1"+foldersOkMsg)
@@ -152,22 +280,28 @@ func TestFoldersCheck_Run(t *testing.T) {
 		{
 			name: "with folder error",
 			endpoints: map[string]any{
-				"/rest/config/devices":               devices,
-				"/rest/config/folders":               folders,
-				"/rest/folder/errors?folder=default": defaultError,
-				compEP:                               &completion,
+				"/rest/config/devices": devices,
+				connsEP:                conns,
+				statsEP:                stats,
+				"/rest/config/folders": folders,
+				errorsEP:               defaultError,
+				compEP:                 &completion,
 			},
 			assertOutput: func(t *testing.T, rawOutput string) {
 				assert.Equal(t, `WARNING: 1/1 folders with errors
 folder: default (Default Folder)
 path: /some file path
-error: some error`, rawOutput)
+error: some error | 'errors_default'=1`+
+					testCompletionPerf("default_XXXXXX2", &completion)+totalPerf,
+					rawOutput)
 			},
 		},
 		{
 			name: "with folder error 2",
 			endpoints: map[string]any{
 				"/rest/config/devices": devices,
+				connsEP:                conns,
+				statsEP:                stats,
 				"/rest/config/folders": []api.FolderConfiguration{
 					folders[0],
 					{
@@ -177,8 +311,8 @@ error: some error`, rawOutput)
 						Path:    "/Default Folder2",
 					},
 				},
-				"/rest/folder/errors?folder=default": defaultError,
-				"/rest/folder/errors?folder=default2": api.FolderErrors{
+				errorsEP: defaultError,
+				errorsEP2: api.FolderErrors{
 					Folder: "default2",
 				},
 				compEP:  &completion,
@@ -188,15 +322,20 @@ error: some error`, rawOutput)
 				assert.Equal(t, `WARNING: 1/2 folders with errors
 folder: default (Default Folder)
 path: /some file path
-error: some error`, rawOutput)
+error: some error | 'errors_default'=1 'errors_default2'=0`+
+					testCompletionPerf("default_XXXXXX2", &completion)+
+					testCompletionPerf("default2_XXXXXX2", &completion)+totalPerf,
+					rawOutput)
 			},
 		},
 		{
 			name: "out of sync",
 			endpoints: map[string]any{
-				"/rest/config/devices":               devices,
-				"/rest/config/folders":               folders,
-				"/rest/folder/errors?folder=default": folderErrors,
+				"/rest/config/devices": devices,
+				connsEP:                conns,
+				statsEP:                stats,
+				"/rest/config/folders": folders,
+				errorsEP:               folderErrors,
 				compEP: &api.FolderCompletion{
 					Completion: 99,
 				},
@@ -204,15 +343,139 @@ error: some error`, rawOutput)
 			assertOutput: func(t *testing.T, rawOutput string) {
 				assert.Equal(t, `WARNING: 1/1 folders out of sync
 folder: default (Default Folder)
-device: XXXXXX2 (device2) - 99%`, rawOutput)
+device: XXXXXX2 (device2) - 99% | 'errors_default'=0`+
+					testCompletionPerf("default_XXXXXX2",
+						&api.FolderCompletion{Completion: 99})+totalPerf,
+					rawOutput)
+			},
+		},
+		{
+			name: "critical completion",
+			with: func(t *testing.T, check *FoldersCheck) {
+				check.WithCompletionThresholds(99, 50)
+			},
+			endpoints: map[string]any{
+				"/rest/config/devices": devices,
+				connsEP:                conns,
+				statsEP:                stats,
+				"/rest/config/folders": folders,
+				errorsEP:               folderErrors,
+				compEP:                 &api.FolderCompletion{Completion: 40},
+			},
+			assertOutput: func(t *testing.T, rawOutput string) {
+				assert.Equal(t, `CRITICAL: 1/1 folders out of sync
+folder: default (Default Folder)
+device: XXXXXX2 (device2) - 40% | 'errors_default'=0`+
+					testCompletionPerf("default_XXXXXX2",
+						&api.FolderCompletion{Completion: 40})+totalPerf,
+					rawOutput)
+			},
+		},
+		{
+			name: "completion above threshold",
+			with: func(t *testing.T, check *FoldersCheck) {
+				check.WithCompletionThresholds(99, 50)
+			},
+			endpoints: map[string]any{
+				"/rest/config/devices": devices,
+				connsEP:                conns,
+				statsEP:                stats,
+				"/rest/config/folders": folders,
+				errorsEP:               folderErrors,
+				compEP:                 &api.FolderCompletion{Completion: 99.9},
+			},
+			assertOutput: func(t *testing.T, rawOutput string) {
+				assert.Equal(t, "OK: 1"+foldersOkMsg+" | 'errors_default'=0"+
+					testCompletionPerf("default_XXXXXX2",
+						&api.FolderCompletion{Completion: 99.9})+totalPerf,
+					rawOutput)
+			},
+		},
+		{
+			name: "folder threshold",
+			with: func(t *testing.T, check *FoldersCheck) {
+				check.WithFolderThresholds(map[string]completionThresholds{
+					"Default Folder": {warn: 90},
+				})
+			},
+			endpoints: map[string]any{
+				"/rest/config/devices": devices,
+				connsEP:                conns,
+				statsEP:                stats,
+				"/rest/config/folders": folders,
+				errorsEP:               folderErrors,
+				compEP:                 &api.FolderCompletion{Completion: 95},
+			},
+			assertOutput: func(t *testing.T, rawOutput string) {
+				assert.Equal(t, "OK: 1"+foldersOkMsg+" | 'errors_default'=0"+
+					testCompletionPerf("default_XXXXXX2",
+						&api.FolderCompletion{Completion: 95})+totalPerf,
+					rawOutput)
+			},
+		},
+		{
+			name: "device threshold",
+			with: func(t *testing.T, check *FoldersCheck) {
+				check.WithFolderThresholds(map[string]completionThresholds{
+					"default": {warn: 90},
+				}).WithDeviceThresholds(map[string]completionThresholds{
+					"device2": {warn: 100, crit: 99},
+				})
+			},
+			endpoints: map[string]any{
+				"/rest/config/devices": devices,
+				connsEP:                conns,
+				statsEP:                stats,
+				"/rest/config/folders": folders,
+				errorsEP:               folderErrors,
+				compEP:                 &api.FolderCompletion{Completion: 95},
+			},
+			assertOutput: func(t *testing.T, rawOutput string) {
+				assert.Equal(t, `CRITICAL: 1/1 folders out of sync
+folder: default (Default Folder)
+device: XXXXXX2 (device2) - 95% | 'errors_default'=0`+
+					testCompletionPerf("default_XXXXXX2",
+						&api.FolderCompletion{Completion: 95})+totalPerf,
+					rawOutput)
+			},
+		},
+		{
+			name: "need thresholds",
+			with: func(t *testing.T, check *FoldersCheck) {
+				check.WithCompletionThresholds(90, 0).
+					WithNeedThresholds(1<<20, 0, 0, 10)
+			},
+			endpoints: map[string]any{
+				"/rest/config/devices": devices,
+				connsEP:                conns,
+				statsEP:                stats,
+				"/rest/config/folders": folders,
+				errorsEP:               folderErrors,
+				compEP: &api.FolderCompletion{
+					Completion: 99, NeedBytes: 1 << 20, NeedItems: 5,
+				},
+			},
+			assertOutput: func(t *testing.T, rawOutput string) {
+				assert.Equal(t, `WARNING: 1/1 folders out of sync
+folder: default (Default Folder)
+device: XXXXXX2 (device2) - 99% | 'errors_default'=0`+
+					testCompletionPerf("default_XXXXXX2", &api.FolderCompletion{
+						Completion: 99, NeedBytes: 1 << 20, NeedItems: 5,
+					})+
+					testCompletionPerf("", &api.FolderCompletion{
+						Completion: 100, NeedBytes: 1 << 20, NeedItems: 5,
+					}),
+					rawOutput)
 			},
 		},
 		{
 			name: "with out of sync folder error",
 			endpoints: map[string]any{
 				"/rest/config/devices": devices,
+				connsEP:                conns,
+				statsEP:                stats,
 				"/rest/config/folders": folders,
-				"/rest/folder/errors?folder=default": api.FolderErrors{
+				errorsEP: api.FolderErrors{
 					Errors: []api.FileError{
 						{
 							Error: "some error",
@@ -229,13 +492,18 @@ device: XXXXXX2 (device2) - 99%`, rawOutput)
 				assert.Equal(t, `WARNING: 1/1 folders with errors
 folder: default (Default Folder)
 path: /some file path
-error: some error`, rawOutput)
+error: some error | 'errors_default'=1`+
+					testCompletionPerf("default_XXXXXX2",
+						&api.FolderCompletion{Completion: 99})+totalPerf,
+					rawOutput)
 			},
 		},
 		{
 			name: "not shared",
 			endpoints: map[string]any{
 				"/rest/config/devices": devices,
+				connsEP:                conns,
+				statsEP:                stats,
 				"/rest/config/folders": []api.FolderConfiguration{
 					{
 						Id:    "default",
@@ -243,13 +511,12 @@ error: some error`, rawOutput)
 						Path:  "/Default Folder",
 					},
 				},
-				"/rest/folder/errors?folder=default": folderErrors,
-				compEP:                               &completion,
+				errorsEP: folderErrors,
+				compEP:   &completion,
 			},
 			assertOutput: func(t *testing.T, rawOutput string) {
-				assert.Equal(t,
-					"WARNING: 1 folder not shared: default (Default Folder)",
-					rawOutput)
+				assert.Equal(t, "WARNING: 1 folder not shared: "+
+					"default (Default Folder) | 'errors_default'=0"+totalPerf, rawOutput)
 			},
 		},
 	}
@@ -260,7 +527,7 @@ error: some error`, rawOutput)
 			if tt.with != nil {
 				tt.with(t, check)
 			}
-			require.Same(t, check, check.Run())
+			require.Same(t, check, check.Run(t.Context()))
 			resp := check.Response()
 			require.NotNil(t, resp)
 			t.Log(resp.GetInfo().RawOutput)
@@ -268,3 +535,531 @@ error: some error`, rawOutput)
 		})
 	}
 }
+
+func TestFoldersCheck_Run_timeout(t *testing.T) {
+	const testId2 = "XXXXXX2-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX"
+
+	check := testNewFoldersCheck(t, map[string]any{
+		"/rest/config/devices": []api.DeviceConfiguration{
+			{DeviceID: testId2, Name: "device2"},
+		},
+		connsEP: api.Connections{},
+		statsEP: map[string]api.DeviceStatistics{},
+		"/rest/config/folders": []api.FolderConfiguration{
+			{
+				Devices: []api.FolderDeviceConfiguration{{DeviceId: testId2}},
+				Id:      "default",
+				Label:   "Default Folder",
+			},
+		},
+		"/rest/folder/errors?folder=default&page=1&perpage=1000": api.FolderErrors{
+			Errors: []api.FileError{{Error: "some error", Path: "/some file path"}},
+			Folder: "default",
+		},
+		"/rest/db/completion?device=" + testId2 + "&folder=default": blockingAPI,
+	})
+
+	ctx := withTestTimeout(t, 50*time.Millisecond)
+	require.Same(t, check, check.Run(ctx))
+	rawOutput := check.Response().GetInfo().RawOutput
+	t.Log(rawOutput)
+	assert.Equal(t, `UNKNOWN: timed out after 50ms while fetching completion of folder default
+1/1 folders with errors
+folder: default (Default Folder)
+path: /some file path
+error: some error | 'errors_default'=1`+totalPerf, rawOutput)
+
+	check = testNewFoldersCheck(t, map[string]any{
+		"/rest/config/devices": []api.DeviceConfiguration{},
+		connsEP:                api.Connections{},
+		statsEP:                map[string]api.DeviceStatistics{},
+		"/rest/config/folders": blockingAPI,
+	})
+	ctx = withTestTimeout(t, 50*time.Millisecond)
+	require.Same(t, check, check.Run(ctx))
+	assert.Equal(t, "UNKNOWN: timed out after 50ms while fetching folders",
+		check.Response().GetInfo().RawOutput)
+}
+
+func TestFoldersCheck_Run_stateFile(t *testing.T) {
+	const testId2 = "XXXXXX2-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX"
+
+	endpoints := map[string]any{
+		"/rest/config/devices": []api.DeviceConfiguration{
+			{DeviceID: testId2, Name: "device2"},
+		},
+		connsEP: api.Connections{},
+		statsEP: map[string]api.DeviceStatistics{},
+		"/rest/config/folders": []api.FolderConfiguration{
+			{
+				Devices: []api.FolderDeviceConfiguration{{DeviceId: testId2}},
+				Id:      "default",
+				Label:   "Default Folder",
+			},
+		},
+		"/rest/folder/errors?folder=default&page=1&perpage=1000": api.FolderErrors{
+			Folder: "default",
+		},
+		"/rest/db/completion?device=" + testId2 + "&folder=default": api.FolderCompletion{
+			Completion: 97, Sequence: 10,
+		},
+	}
+
+	stateFile := filepath.Join(t.TempDir(), "sync.json")
+	newCheck := func() *FoldersCheck {
+		return testNewFoldersCheck(t, endpoints).
+			WithCompletionThresholds(0, 0).
+			WithStateFile(stateFile).
+			WithOutOfSyncThresholds(time.Hour, 0).
+			WithNoProgressThresholds(0, 6*time.Hour)
+	}
+
+	check := newCheck()
+	require.Same(t, check, check.Run(t.Context()))
+	assert.Equal(t, "OK: 1"+foldersOkMsg+" | 'errors_default'=0"+
+		testCompletionPerf("default_XXXXXX2",
+			&api.FolderCompletion{Completion: 97})+totalPerf,
+		check.Response().GetInfo().RawOutput)
+
+	syncState, err := client.LoadSyncState(stateFile)
+	require.NoError(t, err)
+	pair := syncState.Folders["default"][testId2]
+	require.NotNil(t, pair)
+	assert.InDelta(t, 97.0, pair.Completion, 0)
+	assert.Equal(t, int64(10), pair.Sequence)
+
+	pair.OutOfSyncSince = pair.OutOfSyncSince.Add(-2 * time.Hour)
+	pair.ProgressTime = pair.OutOfSyncSince
+	syncState.Update("default", testId2,
+		&api.FolderCompletion{Completion: 97, Sequence: 10}, time.Now())
+	require.NoError(t, syncState.Save())
+
+	check = newCheck()
+	require.Same(t, check, check.Run(t.Context()))
+	assert.Equal(t, `WARNING: 1/1 folders out of sync
+folder: default (Default Folder)
+device: XXXXXX2 (device2) - 97%, out of sync 2h0m0s, no progress 2h0m0s | 'errors_default'=0`+
+		testCompletionPerf("default_XXXXXX2",
+			&api.FolderCompletion{Completion: 97})+totalPerf,
+		check.Response().GetInfo().RawOutput)
+
+	check = newCheck().WithNoProgressThresholds(0, time.Hour)
+	require.Same(t, check, check.Run(t.Context()))
+	assert.Contains(t, check.Response().GetInfo().RawOutput,
+		"CRITICAL: 1/1 folders out of sync")
+
+	check = newCheck().WithCompletionThresholds(0, 98).
+		WithOutOfSyncThresholds(10*time.Hour, 0).WithNoProgressThresholds(0, 0)
+	require.Same(t, check, check.Run(t.Context()))
+	assert.Contains(t, check.Response().GetInfo().RawOutput,
+		"CRITICAL: 1/1 folders out of sync", "completion status wins")
+
+	check = testNewFoldersCheck(t, endpoints).WithStateFile(t.TempDir())
+	require.Same(t, check, check.Run(t.Context()))
+	assert.Contains(t, check.Response().GetInfo().RawOutput,
+		"UNKNOWN: read sync state: ")
+}
+
+func TestFoldersCheck_Run_stateFileTimeout(t *testing.T) {
+	const testId2 = "XXXXXX2-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX"
+	const testId3 = "XXXXXX3-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX"
+
+	stateFile := filepath.Join(t.TempDir(), "sync.json")
+	syncState, err := client.LoadSyncState(stateFile)
+	require.NoError(t, err)
+	outOfSyncSince := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+	for _, id := range []string{testId2, testId3} {
+		syncState.Update("default", id,
+			&api.FolderCompletion{Completion: 97, Sequence: 10}, outOfSyncSince)
+	}
+	require.NoError(t, syncState.Save())
+
+	check := testNewFoldersCheck(t, map[string]any{
+		"/rest/config/devices": []api.DeviceConfiguration{
+			{DeviceID: testId2, Name: "device2"},
+			{DeviceID: testId3, Name: "device3"},
+		},
+		connsEP: api.Connections{},
+		statsEP: map[string]api.DeviceStatistics{},
+		"/rest/config/folders": []api.FolderConfiguration{
+			{
+				Devices: []api.FolderDeviceConfiguration{
+					{DeviceId: testId2},
+					{DeviceId: testId3},
+				},
+				Id:    "default",
+				Label: "Default Folder",
+			},
+		},
+		"/rest/folder/errors?folder=default&page=1&perpage=1000": api.FolderErrors{
+			Folder: "default",
+		},
+		"/rest/db/completion?device=" + testId2 + "&folder=default": api.FolderCompletion{
+			Completion: 100,
+		},
+		"/rest/db/completion?device=" + testId3 + "&folder=default": blockingAPI,
+	}).WithStateFile(stateFile)
+
+	ctx := withTestTimeout(t, 50*time.Millisecond)
+	require.Same(t, check, check.Run(ctx))
+	assert.Contains(t, check.Response().GetInfo().RawOutput,
+		"UNKNOWN: timed out after 50ms while fetching completion of folder default")
+
+	syncState, err = client.LoadSyncState(stateFile)
+	require.NoError(t, err)
+	assert.NotContains(t, syncState.Folders["default"], testId2, "in sync")
+	pair := syncState.Folders["default"][testId3]
+	require.NotNil(t, pair, "not fetched, but kept")
+	assert.True(t, outOfSyncSince.Equal(pair.OutOfSyncSince))
+}
+
+func TestFoldersCheck_Run_offline(t *testing.T) {
+	const testId2 = "XXXXXX2-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX"
+	const testId3 = "XXXXXX3-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX"
+
+	endpoints := map[string]any{
+		"/rest/config/devices": []api.DeviceConfiguration{
+			{DeviceID: testId2, Name: "device2"},
+			{DeviceID: testId3, Name: "device3"},
+		},
+		"/rest/config/folders": []api.FolderConfiguration{
+			{
+				Devices: []api.FolderDeviceConfiguration{
+					{DeviceId: testId2}, {DeviceId: testId3},
+				},
+				Id:    "default",
+				Label: "Default Folder",
+			},
+		},
+		connsEP: api.Connections{
+			Connections: map[string]api.ConnectionStats{
+				testId2: {},
+				testId3: {Paused: true},
+			},
+		},
+		statsEP: map[string]api.DeviceStatistics{
+			testId2: {LastSeen: time.Now().Add(-2 * time.Hour)},
+		},
+		"/rest/folder/errors?folder=default&page=1&perpage=1000": api.FolderErrors{
+			Folder: "default",
+		},
+		"/rest/db/completion?device=" + testId2 + "&folder=default": api.FolderCompletion{
+			Completion:  97,
+			RemoteState: remoteUnknown,
+		},
+		"/rest/db/completion?device=" + testId3 + "&folder=default": api.FolderCompletion{
+			Completion:  100,
+			RemoteState: remotePaused,
+		},
+	}
+
+	perfData := " | 'errors_default'=0" +
+		testCompletionPerf("default_XXXXXX2",
+			&api.FolderCompletion{Completion: 97}) +
+		testCompletionPerf("default_XXXXXX3",
+			&api.FolderCompletion{Completion: 100}) + totalPerf
+
+	tests := []struct {
+		name     string
+		with     func(t *testing.T, check *FoldersCheck)
+		expected string
+	}{
+		{
+			name: "default",
+			expected: "OK: 1" + foldersOkMsg + `
+1 devices offline
+device: XXXXXX2 (device2) - last seen 2h0m0s ago` + perfData,
+		},
+		{
+			name: "offline",
+			with: func(t *testing.T, check *FoldersCheck) {
+				check.WithOffline(monitoringplugin.WARNING, 0)
+			},
+			expected: `WARNING: 1 devices offline
+device: XXXXXX2 (device2) - last seen 2h0m0s ago` + perfData,
+		},
+		{
+			name: "in grace",
+			with: func(t *testing.T, check *FoldersCheck) {
+				check.WithOffline(monitoringplugin.OK, 3*time.Hour)
+			},
+			expected: "OK: 1" + foldersOkMsg + `
+1 devices offline
+device: XXXXXX2 (device2) - last seen 2h0m0s ago` + perfData,
+		},
+		{
+			name: "after grace",
+			with: func(t *testing.T, check *FoldersCheck) {
+				check.WithOffline(monitoringplugin.CRITICAL, time.Hour)
+			},
+			expected: `CRITICAL: 1 devices offline
+device: XXXXXX2 (device2) - last seen 2h0m0s ago
+1/1 folders out of sync
+folder: default (Default Folder)
+device: XXXXXX2 (device2) - 97%` + perfData,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := testNewFoldersCheck(t, endpoints)
+			if tt.with != nil {
+				tt.with(t, check)
+			}
+			require.Same(t, check, check.Run(t.Context()))
+			rawOutput := check.Response().GetInfo().RawOutput
+			t.Log(rawOutput)
+			assert.Equal(t, tt.expected, rawOutput)
+		})
+	}
+}
+
+func TestFoldersCheck_Run_offlineDefaults(t *testing.T) {
+	const testId2 = "XXXXXX2-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX"
+
+	comp := api.FolderCompletion{Completion: 97, RemoteState: remoteUnknown}
+	check := testNewFoldersCheck(t, map[string]any{
+		"/rest/config/devices": []api.DeviceConfiguration{
+			{DeviceID: testId2, Name: "device2"},
+		},
+		"/rest/config/folders": []api.FolderConfiguration{
+			{
+				Devices: []api.FolderDeviceConfiguration{{DeviceId: testId2}},
+				Id:      "default",
+				Label:   "Default Folder",
+			},
+		},
+		connsEP: api.Connections{
+			Connections: map[string]api.ConnectionStats{testId2: {}},
+		},
+		statsEP: map[string]api.DeviceStatistics{
+			testId2: {LastSeen: time.Now().Add(-48 * time.Hour)},
+		},
+		"/rest/folder/errors?folder=default&page=1&perpage=1000": api.FolderErrors{
+			Folder: "default",
+		},
+		"/rest/db/completion?device=" + testId2 + "&folder=default": comp,
+	})
+
+	require.Same(t, check, check.Run(t.Context()))
+	assert.Equal(t, `WARNING: 1/1 folders out of sync
+folder: default (Default Folder)
+device: XXXXXX2 (device2) - 97%
+1 devices offline
+device: XXXXXX2 (device2) - last seen 48h0m0s ago | 'errors_default'=0`+
+		testCompletionPerf("default_XXXXXX2", &comp)+
+		totalPerf,
+		check.Response().GetInfo().RawOutput)
+}
+
+func TestFoldersCheck_Run_remoteStates(t *testing.T) {
+	const testId2 = "XXXXXX2-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX"
+	const testId3 = "XXXXXX3-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX"
+	const testId4 = "XXXXXX4-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX"
+
+	endpoints := map[string]any{
+		"/rest/config/devices": []api.DeviceConfiguration{
+			{DeviceID: testId2, Name: "device2"},
+			{DeviceID: testId3, Name: "device3"},
+			{DeviceID: testId4, Name: "device4"},
+		},
+		"/rest/config/folders": []api.FolderConfiguration{
+			{
+				Devices: []api.FolderDeviceConfiguration{
+					{DeviceId: testId2}, {DeviceId: testId3}, {DeviceId: testId4},
+				},
+				Id:    "default",
+				Label: "Default Folder",
+			},
+		},
+		connsEP: api.Connections{
+			Connections: map[string]api.ConnectionStats{
+				testId2: {Connected: true},
+				testId3: {Connected: true},
+				testId4: {Connected: true},
+			},
+		},
+		statsEP: map[string]api.DeviceStatistics{},
+		"/rest/folder/errors?folder=default&page=1&perpage=1000": api.FolderErrors{
+			Folder: "default",
+		},
+		"/rest/db/completion?device=" + testId2 + "&folder=default": api.FolderCompletion{
+			RemoteState: remoteNotSharing,
+		},
+		"/rest/db/completion?device=" + testId3 + "&folder=default": api.FolderCompletion{
+			RemoteState: remoteUnknown,
+		},
+		"/rest/db/completion?device=" + testId4 + "&folder=default": api.FolderCompletion{
+			RemoteState: remotePaused,
+		},
+	}
+
+	perfData := " | 'errors_default'=0" +
+		testCompletionPerf("default_XXXXXX2", &api.FolderCompletion{}) +
+		testCompletionPerf("default_XXXXXX3", &api.FolderCompletion{}) +
+		testCompletionPerf("default_XXXXXX4", &api.FolderCompletion{}) + totalPerf
+
+	tests := []struct {
+		name     string
+		with     func(t *testing.T, check *FoldersCheck)
+		expected string
+	}{
+		{
+			name: "default",
+			expected: "WARNING: 1 folders not shared back: " +
+				"default (Default Folder) on XXXXXX2 (device2)\n" +
+				"1 folders in unknown state: " +
+				"default (Default Folder) on XXXXXX3 (device3)" + perfData,
+		},
+		{
+			name: "with remote states",
+			with: func(t *testing.T, check *FoldersCheck) {
+				check.WithRemoteStates(monitoringplugin.CRITICAL,
+					monitoringplugin.OK, monitoringplugin.WARNING)
+			},
+			expected: "CRITICAL: 1 folders not shared back: " +
+				"default (Default Folder) on XXXXXX2 (device2)\n" +
+				"1 folders paused remotely: " +
+				"default (Default Folder) on XXXXXX4 (device4)" + perfData,
+		},
+		{
+			name: "disabled",
+			with: func(t *testing.T, check *FoldersCheck) {
+				check.WithRemoteStates(monitoringplugin.OK, monitoringplugin.OK,
+					monitoringplugin.OK)
+			},
+			expected: "OK: 1" + foldersOkMsg + perfData,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := testNewFoldersCheck(t, endpoints)
+			if tt.with != nil {
+				tt.with(t, check)
+			}
+			require.Same(t, check, check.Run(t.Context()))
+			rawOutput := check.Response().GetInfo().RawOutput
+			t.Log(rawOutput)
+			assert.Equal(t, tt.expected, rawOutput)
+		})
+	}
+}
+
+func TestFoldersCheck_Run_remoteStatesOffline(t *testing.T) {
+	const testId2 = "XXXXXX2-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX"
+	const testId3 = "XXXXXX3-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX"
+
+	check := testNewFoldersCheck(t, map[string]any{
+		"/rest/config/devices": []api.DeviceConfiguration{
+			{DeviceID: testId2, Name: "device2"},
+			{DeviceID: testId3, Name: "device3"},
+		},
+		"/rest/config/folders": []api.FolderConfiguration{
+			{
+				Devices: []api.FolderDeviceConfiguration{
+					{DeviceId: testId2}, {DeviceId: testId3},
+				},
+				Id:    "default",
+				Label: "Default Folder",
+			},
+		},
+		connsEP: api.Connections{
+			Connections: map[string]api.ConnectionStats{
+				testId2: {},
+				testId3: {},
+			},
+		},
+		statsEP: map[string]api.DeviceStatistics{
+			testId2: {LastSeen: time.Now().Add(-2 * time.Hour)},
+			testId3: {LastSeen: time.Now().Add(-2 * time.Hour)},
+		},
+		"/rest/folder/errors?folder=default&page=1&perpage=1000": api.FolderErrors{
+			Folder: "default",
+		},
+		"/rest/db/completion?device=" + testId2 + "&folder=default": api.FolderCompletion{
+			Completion:  100,
+			RemoteState: remoteNotSharing,
+		},
+		"/rest/db/completion?device=" + testId3 + "&folder=default": api.FolderCompletion{
+			Completion:  100,
+			RemoteState: remoteUnknown,
+		},
+	}).WithOffline(monitoringplugin.WARNING, time.Hour).
+		WithRemoteStates(monitoringplugin.CRITICAL, monitoringplugin.CRITICAL,
+			monitoringplugin.CRITICAL)
+
+	require.Same(t, check, check.Run(t.Context()))
+	assert.Equal(t, `WARNING: 2 devices offline
+device: XXXXXX2 (device2) - last seen 2h0m0s ago
+device: XXXXXX3 (device3) - last seen 2h0m0s ago | 'errors_default'=0`+
+		testCompletionPerf("default_XXXXXX2",
+			&api.FolderCompletion{Completion: 100})+
+		testCompletionPerf("default_XXXXXX3",
+			&api.FolderCompletion{Completion: 100})+totalPerf,
+		check.Response().GetInfo().RawOutput)
+}
+
+func TestFoldersCheck_Run_manyErrors(t *testing.T) {
+	check := testNewFoldersCheck(t, map[string]any{
+		"/rest/config/devices": []api.DeviceConfiguration{},
+		connsEP:                api.Connections{},
+		statsEP:                map[string]api.DeviceStatistics{},
+		"/rest/config/folders": []api.FolderConfiguration{
+			{Id: "default", Label: "Default Folder"},
+		},
+		"/rest/folder/errors?folder=default&page=1&perpage=1000": api.FolderErrors{
+			Errors: []api.FileError{
+				{Error: "permission denied", Path: "/path1"},
+				{Error: "no space left", Path: "/path2"},
+				{Error: "permission denied", Path: "/path3"},
+				{Error: "file name too long", Path: "/path4"},
+				{Error: "permission denied", Path: "/path5"},
+			},
+			Folder: "default",
+		},
+	}).WithErrorMessages(2)
+
+	require.Same(t, check, check.Run(t.Context()))
+	assert.Equal(t, `WARNING: 1/1 folders with errors
+folder: default (Default Folder)
+errors: 5
+error: permission denied (3 files, first: /path1)
+error: no space left (1 files, first: /path2)
+and 1 more distinct errors | 'errors_default'=5`+totalPerf,
+		check.Response().GetInfo().RawOutput)
+}
+
+func TestFolderErrors(t *testing.T) {
+	var folderErrors folderErrors
+	assert.Zero(t, folderErrors.Total())
+
+	folderErrors.Add(&api.FileError{Error: "error1", Path: "path1"})
+	folderErrors.Add(&api.FileError{Error: "error2", Path: "path2"})
+	folderErrors.Add(&api.FileError{Error: "error1", Path: "path3"})
+	assert.Equal(t, 3, folderErrors.Total())
+	assert.Equal(t, []folderErrorGroup{
+		{err: "error1", path: "path1", count: 2},
+		{err: "error2", path: "path2", count: 1},
+	}, folderErrors.groups)
+}
+
+// totalPerf is performance data of totals without needed items.
+const totalPerf = " 'completion'=100% 'need_bytes'=0B 'need_items'=0" +
+	" 'need_deletes'=0"
+
+// testCompletionPerf returns performance data of comp with label.
+func testCompletionPerf(label string, comp *api.FolderCompletion) string {
+	if label != "" {
+		label = "_" + label
+	}
+	return fmt.Sprintf(" 'completion%[1]v'=%[2]v%% 'need_bytes%[1]v'=%[3]vB"+
+		" 'need_items%[1]v'=%[4]v 'need_deletes%[1]v'=%[5]v",
+		label, comp.Completion, comp.NeedBytes, comp.NeedItems,
+		comp.NeedDeletes)
+}
+
+const (
+	connsEP = "/rest/system/connections"
+	statsEP = "/rest/stats/device"
+)
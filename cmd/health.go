@@ -24,7 +24,9 @@ Checks syncthing server handles REST API requests and has no system errors. In
 case of errors, outputs last system error.`,
 
 	Run: func(cmd *cobra.Command, args []string) {
-		NewHealthCheck(mustAPIClient()).Run().Response().OutputAndExit()
+		ctx, cancel := checkContext(cmd)
+		defer cancel()
+		NewHealthCheck(mustAPIClient()).Run(ctx).Response().OutputAndExit()
 	},
 }
 
@@ -54,14 +56,19 @@ func (self *HealthCheck) Response() *monitoringplugin.Response {
 	return self.resp
 }
 
-func (self *HealthCheck) Run() *HealthCheck {
-	ctx := context.Background()
+func (self *HealthCheck) Run(ctx context.Context) *HealthCheck {
+	defer outputRetries(self.resp, self.client)
+
 	if !self.checkHealth(ctx) || !self.fetch(ctx) {
 		return self
 	}
 
-	self.resp.WithDefaultOkMessage(healthOkMsg + self.systemName())
-	self.outputConnected()
+	if self.system != nil {
+		self.resp.WithDefaultOkMessage(healthOkMsg + self.systemName())
+	}
+	if self.conns != nil {
+		self.outputConnected()
+	}
 
 	if len(self.sysErrors) > 0 {
 		self.checkSysErrors(self.sysErrors)
@@ -70,8 +77,9 @@ func (self *HealthCheck) Run() *HealthCheck {
 }
 
 func (self *HealthCheck) checkHealth(ctx context.Context) bool {
-	return !self.resp.UpdateStatusOnError(self.client.Health(ctx),
-		monitoringplugin.CRITICAL, "", true)
+	err := self.client.Health(ctx)
+	return checkFetchError(ctx, self.resp, newFetchError("health", err)) &&
+		err == nil
 }
 
 func (self *HealthCheck) fetch(parentCtx context.Context) bool {
@@ -82,15 +90,13 @@ func (self *HealthCheck) fetch(parentCtx context.Context) bool {
 	g.Go(func() error { return self.fetchSystemStatus(ctx) })
 	g.Go(func() error { return self.fetchDevices(ctx) })
 	g.Go(func() error { return self.fetchConns(ctx) })
-
-	self.resp.UpdateStatusOnError(g.Wait(), monitoringplugin.CRITICAL, "", true)
-	return self.resp.GetStatusCode() == monitoringplugin.OK
+	return checkFetchError(parentCtx, self.resp, g.Wait())
 }
 
 func (self *HealthCheck) fetchSysErrors(ctx context.Context) error {
 	sysErrors, err := self.client.SystemErrors(ctx)
 	if err != nil {
-		return err
+		return newFetchError("system errors", err)
 	} else if len(sysErrors) > 0 {
 		self.sysErrors = sysErrors
 	}
@@ -100,7 +106,7 @@ func (self *HealthCheck) fetchSysErrors(ctx context.Context) error {
 func (self *HealthCheck) fetchSystemStatus(ctx context.Context) error {
 	system, err := self.client.SystemStatus(ctx)
 	if err != nil {
-		return err
+		return newFetchError("system status", err)
 	}
 	self.system = system
 	return nil
@@ -109,7 +115,7 @@ func (self *HealthCheck) fetchSystemStatus(ctx context.Context) error {
 func (self *HealthCheck) fetchDevices(ctx context.Context) error {
 	devices, err := self.client.Devices(ctx)
 	if err != nil {
-		return err
+		return newFetchError("devices", err)
 	}
 
 	self.devices = make(map[string]api.DeviceConfiguration, len(devices))
@@ -123,7 +129,7 @@ func (self *HealthCheck) fetchDevices(ctx context.Context) error {
 func (self *HealthCheck) fetchConns(ctx context.Context) error {
 	conns, err := self.client.Connections(ctx)
 	if err != nil {
-		return err
+		return newFetchError("connections", err)
 	}
 	self.conns = conns
 	return nil
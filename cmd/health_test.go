@@ -1,10 +1,14 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/dsh2dsh/go-monitoringplugin/v2"
 	"github.com/stretchr/testify/assert"
@@ -48,10 +52,13 @@ func fakeAPI(t *testing.T, endpoints map[string]any,
 		require.NotNil(t, req.URL)
 		t.Log(req.URL)
 		v, ok := endpoints[req.URL.String()]
+		if fn, ok := v.(testHttpDoer); ok {
+			return fn(req)
+		}
 		r := httptest.NewRecorder()
 		if !ok {
 			r.WriteHeader(http.StatusNotFound)
-			return r.Result(), nil
+			return testResult(r, req), nil
 		}
 		r.Header().Set("Content-Type", "application/json")
 		r.WriteHeader(http.StatusOK)
@@ -66,10 +73,42 @@ func fakeAPI(t *testing.T, endpoints map[string]any,
 		}
 		_, err := r.Write(b)
 		require.NoError(t, err)
-		return r.Result(), nil
+		return testResult(r, req), nil
 	}
 }
 
+func testResult(r *httptest.ResponseRecorder, req *http.Request,
+) *http.Response {
+	resp := r.Result()
+	resp.Request = req
+	return resp
+}
+
+// blockingAPI blocks until request context is done, like a stuck daemon.
+var blockingAPI = testHttpDoer(func(req *http.Request) (*http.Response, error) {
+	<-req.Context().Done()
+	return nil, req.Context().Err()
+})
+
+// statusAPI responds with statusCode and empty body.
+func statusAPI(statusCode int) testHttpDoer {
+	return func(req *http.Request) (*http.Response, error) {
+		r := httptest.NewRecorder()
+		r.WriteHeader(statusCode)
+		return testResult(r, req), nil
+	}
+}
+
+func withTestTimeout(t *testing.T, timeout time.Duration) context.Context {
+	origTimeout := checkTimeout
+	t.Cleanup(func() { checkTimeout = origTimeout })
+	checkTimeout = timeout
+
+	ctx, cancel := context.WithTimeout(t.Context(), timeout)
+	t.Cleanup(cancel)
+	return ctx
+}
+
 func TestHealthCheck_applyOptionsWithResp(t *testing.T) {
 	resp := monitoringplugin.NewResponse("def ok msg")
 	check := &HealthCheck{resp: resp}
@@ -225,46 +264,68 @@ func TestHealthCheck_Run(t *testing.T) {
 		assertOutput func(t *testing.T, rawOutput string)
 	}{
 		{
-			name: "crit health",
+			name: "unknown health",
+			assertOutput: func(t *testing.T, rawOutput string) {
+				assert.Contains(t, rawOutput,
+					"UNKNOWN: /rest/noauth/health not found")
+			},
+		},
+		{
+			name: "unknown access denied",
+			endpoints: map[string]any{
+				"/rest/noauth/health": statusAPI(http.StatusForbidden),
+			},
+			assertOutput: func(t *testing.T, rawOutput string) {
+				assert.Equal(t, "UNKNOWN: access denied, check API key (error: "+
+					"health: unexpected syncthing response: 403 Forbidden ())",
+					rawOutput)
+			},
+		},
+		{
+			name: "crit unavailable",
+			endpoints: map[string]any{
+				"/rest/noauth/health": statusAPI(http.StatusServiceUnavailable),
+			},
 			assertOutput: func(t *testing.T, rawOutput string) {
-				assert.Contains(t, rawOutput, "CRITICAL: health:")
+				assert.Equal(t, "CRITICAL: health: unexpected syncthing response: "+
+					"503 Service Unavailable ()", rawOutput)
 			},
 		},
 		{
-			name: "crit with health",
+			name: "unknown with health",
 			endpoints: map[string]any{
 				"/rest/noauth/health": healthJSON,
 			},
 			assertOutput: func(t *testing.T, rawOutput string) {
-				assert.Contains(t, rawOutput, "CRITICAL: ")
+				assert.Contains(t, rawOutput, "UNKNOWN: /rest/")
 				assert.Contains(t, rawOutput, "404 Not Found")
 			},
 		},
 		{
-			name: "crit without devices and system status",
+			name: "unknown without devices and system status",
 			endpoints: map[string]any{
 				"/rest/noauth/health": healthJSON,
 				"/rest/system/error":  systemErrorsJSON,
 			},
 			assertOutput: func(t *testing.T, rawOutput string) {
-				assert.Contains(t, rawOutput, "CRITICAL: ")
+				assert.Contains(t, rawOutput, "UNKNOWN: /rest/")
 				assert.Contains(t, rawOutput, "404 Not Found")
 			},
 		},
 		{
-			name: "crit without system status",
+			name: "unknown without system status",
 			endpoints: map[string]any{
 				"/rest/noauth/health":  healthJSON,
 				"/rest/config/devices": devicesJSON,
 				"/rest/system/error":   systemErrorsJSON,
 			},
 			assertOutput: func(t *testing.T, rawOutput string) {
-				assert.Contains(t, rawOutput, "CRITICAL: ")
+				assert.Contains(t, rawOutput, "UNKNOWN: /rest/")
 				assert.Contains(t, rawOutput, "404 Not Found")
 			},
 		},
 		{
-			name: "crit without connections",
+			name: "unknown without connections",
 			endpoints: map[string]any{
 				"/rest/noauth/health":  healthJSON,
 				"/rest/config/devices": devicesJSON,
@@ -272,7 +333,7 @@ func TestHealthCheck_Run(t *testing.T) {
 				"/rest/system/status":  systemStatusJSON,
 			},
 			assertOutput: func(t *testing.T, rawOutput string) {
-				assert.Contains(t, rawOutput, "CRITICAL: ")
+				assert.Contains(t, rawOutput, "UNKNOWN: /rest/")
 				assert.Contains(t, rawOutput, "404 Not Found")
 			},
 		},
@@ -318,7 +379,7 @@ last error at: 2024-03-28 20:15:11`)
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			check := testNewHealthCheck(t, tt.endpoints)
-			require.Same(t, check, check.Run())
+			require.Same(t, check, check.Run(t.Context()))
 			resp := check.Response()
 			require.NotNil(t, resp)
 			t.Log(resp.GetInfo().RawOutput)
@@ -326,3 +387,62 @@ last error at: 2024-03-28 20:15:11`)
 		})
 	}
 }
+
+func TestHealthCheck_Run_timeout(t *testing.T) {
+	check := testNewHealthCheck(t, map[string]any{
+		"/rest/noauth/health":      `{ "status": "OK" }`,
+		"/rest/config/devices":     []api.DeviceConfiguration{},
+		"/rest/system/status":      api.SystemStatus{},
+		"/rest/system/connections": blockingAPI,
+		"/rest/system/error": api.SystemErrors{
+			Errors: []api.LogLine{{Message: "some error"}},
+		},
+	})
+
+	ctx := withTestTimeout(t, 50*time.Millisecond)
+	require.Same(t, check, check.Run(ctx))
+	rawOutput := check.Response().GetInfo().RawOutput
+	t.Log(rawOutput)
+	assert.Contains(t, rawOutput,
+		"UNKNOWN: timed out after 50ms while fetching connections")
+	assert.Contains(t, rawOutput, "1 system error(s): some error")
+
+	check = testNewHealthCheck(t, map[string]any{
+		"/rest/noauth/health": blockingAPI,
+	})
+	ctx = withTestTimeout(t, 50*time.Millisecond)
+	require.Same(t, check, check.Run(ctx))
+	assert.Equal(t, "UNKNOWN: timed out after 50ms while fetching health",
+		check.Response().GetInfo().RawOutput)
+}
+
+func TestHealthCheck_Run_retries(t *testing.T) {
+	fakeDoer := fakeAPI(t, map[string]any{
+		"/rest/noauth/health":      `{ "status": "OK" }`,
+		"/rest/config/devices":     []api.DeviceConfiguration{},
+		"/rest/system/status":      api.SystemStatus{},
+		"/rest/system/connections": api.Connections{},
+		"/rest/system/error":       api.SystemErrors{},
+	})
+
+	var failed atomic.Bool
+	flakyDoer := testHttpDoer(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path == "/rest/system/status" &&
+			failed.CompareAndSwap(false, true) {
+			return nil, syscall.ECONNRESET
+		}
+		return fakeDoer(req)
+	})
+
+	c, err := client.New("/", client.WithRetry(1, 0),
+		func(c *client.Client) error {
+			return c.NewClientWithResponses(api.WithHTTPClient(flakyDoer))
+		})
+	require.NoError(t, err)
+
+	check := NewHealthCheck(c)
+	require.Same(t, check, check.Run(t.Context()))
+	assert.Equal(t, `OK: syncthing server alive: 
+connected: 0
+retries: 1 | 'connected'=0`, check.Response().GetInfo().RawOutput)
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dsh2dsh/go-monitoringplugin/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/dsh2dsh/check_syncthing/client"
+	"github.com/dsh2dsh/check_syncthing/client/api"
+)
+
+const (
+	listenersOkMsg = "listeners"
+
+	listenerQUIC  = "quic"
+	listenerRelay = "relay"
+	listenerTCP   = "tcp"
+)
+
+var tcpListenerStatus, quicListenerStatus, relayListenerStatus int
+
+var listenersCmd = cobra.Command{
+	Use:   "listeners",
+	Short: "Check syncthing listeners",
+	Long: `Check syncthing listeners.
+
+It outputs errors of listeners, like port already in use or unreachable relay
+pool, with status given by --tcp, --quic and --relay. Status "ok" ignores
+errors of that kind of listeners.
+
+Outputs number of working listeners as performance data.`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := checkContext(cmd)
+		defer cancel()
+		NewListenersCheck(mustAPIClient()).
+			WithStatus(tcpListenerStatus, quicListenerStatus, relayListenerStatus).
+			Run(ctx).Response().OutputAndExit()
+	},
+}
+
+func init() {
+	listenersCmd.Flags().Var(
+		newStatusValue(monitoringplugin.CRITICAL, &tcpListenerStatus),
+		"tcp", "status of TCP listener errors")
+	listenersCmd.Flags().Var(
+		newStatusValue(monitoringplugin.WARNING, &quicListenerStatus),
+		"quic", "status of QUIC listener errors")
+	listenersCmd.Flags().Var(
+		newStatusValue(monitoringplugin.WARNING, &relayListenerStatus),
+		"relay", "status of relay listener errors")
+}
+
+func NewListenersCheck(apiClient *client.Client) *ListenersCheck {
+	c := &ListenersCheck{client: apiClient}
+	return c.WithStatus(tcpListenerStatus, quicListenerStatus,
+		relayListenerStatus).applyOptions()
+}
+
+type ListenersCheck struct {
+	client *client.Client
+	resp   *monitoringplugin.Response
+
+	statuses map[string]int
+}
+
+func (self *ListenersCheck) applyOptions() *ListenersCheck {
+	if self.resp == nil {
+		self.resp = monitoringplugin.NewResponse(listenersOkMsg)
+	}
+	return self
+}
+
+// WithStatus configures status of TCP, QUIC and relay listener errors.
+func (self *ListenersCheck) WithStatus(tcp, quic, relay int) *ListenersCheck {
+	self.statuses = map[string]int{
+		listenerTCP:   tcp,
+		listenerQUIC:  quic,
+		listenerRelay: relay,
+	}
+	return self
+}
+
+func (self *ListenersCheck) Response() *monitoringplugin.Response {
+	return self.resp
+}
+
+func (self *ListenersCheck) Run(ctx context.Context) *ListenersCheck {
+	defer outputRetries(self.resp, self.client)
+
+	system, err := self.client.SystemStatus(ctx)
+	if !checkFetchError(ctx, self.resp, newFetchError("system status", err)) ||
+		err != nil {
+		return self
+	}
+
+	services := newServiceEntries(system.ConnectionServiceStatus, listenerKind,
+		func(e api.ListenerStatusEntry) string { return e.Error })
+	if len(services) == 0 {
+		self.resp.UpdateStatus(monitoringplugin.WARNING, "no listeners")
+		return self
+	}
+
+	working := checkServices(self.resp, services, self.statuses)
+	self.resp.WithDefaultOkMessage(servicesOkMsg(working, len(services),
+		listenersOkMsg))
+	return self
+}
+
+// listenerKind returns kind of listener by its URL, like
+// "tcp://0.0.0.0:22000" or "dynamic+https://relays.syncthing.net/endpoint".
+func listenerKind(name string) string {
+	scheme, _, _ := strings.Cut(name, "://")
+	switch {
+	case strings.HasPrefix(scheme, listenerTCP):
+		return listenerTCP
+	case strings.HasPrefix(scheme, listenerQUIC):
+		return listenerQUIC
+	case scheme == listenerRelay || strings.HasPrefix(scheme, "dynamic+"):
+		return listenerRelay
+	}
+	return scheme
+}
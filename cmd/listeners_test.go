@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/dsh2dsh/go-monitoringplugin/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dsh2dsh/check_syncthing/client/api"
+)
+
+func TestNewListenersCheck(t *testing.T) {
+	check := testNewListenersCheck(t, nil)
+	resp := check.Response()
+	require.NotNil(t, resp)
+	assert.Equal(t, "OK: "+listenersOkMsg, resp.GetInfo().RawOutput)
+}
+
+func testNewListenersCheck(t *testing.T, endpoints map[string]any,
+) *ListenersCheck {
+	check := NewListenersCheck(newTestClient(t, fakeAPI(t, endpoints)))
+	require.NotNil(t, check)
+	return check
+}
+
+func TestListenersCheck_applyOptionsWithResp(t *testing.T) {
+	resp := monitoringplugin.NewResponse("def ok msg")
+	check := &ListenersCheck{resp: resp}
+	require.Same(t, check, check.applyOptions())
+	assert.Same(t, resp, check.Response())
+}
+
+func TestListenersCheck_Run(t *testing.T) {
+	const relayName = "dynamic+https://relays.syncthing.net/endpoint"
+
+	listeners := func(tcpErr, quicErr, relayErr string,
+	) map[string]api.ListenerStatusEntry {
+		return map[string]api.ListenerStatusEntry{
+			"tcp://0.0.0.0:22000":  {Error: tcpErr},
+			"quic://0.0.0.0:22000": {Error: quicErr},
+			relayName:              {Error: relayErr},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		with      func(t *testing.T, check *ListenersCheck)
+		listeners map[string]api.ListenerStatusEntry
+		expected  string
+	}{
+		{
+			name:      "OK",
+			listeners: listeners("", "", ""),
+			expected:  "OK: 3/3 " + listenersOkMsg + " working | 'working'=3;;;0;3",
+		},
+		{
+			name:     "no listeners",
+			expected: "WARNING: no listeners",
+		},
+		{
+			name: "tcp failed",
+			listeners: listeners("listen tcp 0.0.0.0:22000: address already in use",
+				"", "context deadline exceeded"),
+			expected: `CRITICAL: tcp://0.0.0.0:22000: listen tcp 0.0.0.0:22000: address already in use
+` + relayName + `: context deadline exceeded | 'working'=1;;;0;3`,
+		},
+		{
+			name: "relay ignored",
+			with: func(t *testing.T, check *ListenersCheck) {
+				check.WithStatus(monitoringplugin.CRITICAL, monitoringplugin.WARNING,
+					monitoringplugin.OK)
+			},
+			listeners: listeners("", "", "context deadline exceeded"),
+			expected:  "OK: 2/3 " + listenersOkMsg + " working | 'working'=2;;;0;3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := testNewListenersCheck(t, map[string]any{
+				"/rest/system/status": api.SystemStatus{
+					ConnectionServiceStatus: tt.listeners,
+				},
+			})
+			if tt.with != nil {
+				tt.with(t, check)
+			}
+			require.Same(t, check, check.Run(t.Context()))
+			rawOutput := check.Response().GetInfo().RawOutput
+			t.Log(rawOutput)
+			assert.Equal(t, tt.expected, rawOutput)
+		})
+	}
+}
+
+func TestListenerKind(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"tcp://0.0.0.0:22000", listenerTCP},
+		{"tcp4://0.0.0.0:22000", listenerTCP},
+		{"quic://0.0.0.0:22000", listenerQUIC},
+		{"relay://203.0.113.1:22067", listenerRelay},
+		{"dynamic+https://relays.syncthing.net/endpoint", listenerRelay},
+		{"foo://bar", "foo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, listenerKind(tt.name))
+		})
+	}
+}
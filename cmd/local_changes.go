@@ -0,0 +1,246 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/dsh2dsh/go-monitoringplugin/v2"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/dsh2dsh/check_syncthing/client"
+	"github.com/dsh2dsh/check_syncthing/client/api"
+)
+
+const (
+	localChangesOkMsg = " receive-only folders" // N receive-only folders
+
+	folderTypeReceiveOnly = "receiveonly"
+	localChangedPerPage   = 1000
+)
+
+var (
+	warnLocalChanges, critLocalChanges int
+	localChangedFiles                  int
+)
+
+var localChangesCmd = cobra.Command{
+	Use:   "local-changes",
+	Short: "Check local changes of receive-only folders",
+	Long: `Check local changes of receive-only folders.
+
+Receive-only folders must not be changed locally. It counts locally changed
+items of every receive-only folder and outputs warning or critical status, if
+number of them reaches given thresholds. Zero threshold disables it. Paths of
+first --files changed items are outputted too.
+
+Outputs number of locally changed items of every receive-only folder as
+performance data.`,
+
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if localChangedFiles < 0 {
+			return fmt.Errorf("--files %v is negative", localChangedFiles)
+		}
+		return nil
+	},
+
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := checkContext(cmd)
+		defer cancel()
+		NewLocalChangesCheck(mustAPIClient()).
+			WithExcludeFolders(excludeFolders).
+			WithThresholds(warnLocalChanges, critLocalChanges).
+			WithFiles(localChangedFiles).
+			Run(ctx).Response().OutputAndExit()
+	},
+}
+
+func init() {
+	localChangesCmd.Flags().IntVarP(&warnLocalChanges, "warn", "w", 1,
+		"warning threshold of locally changed items")
+	localChangesCmd.Flags().IntVarP(&critLocalChanges, "crit", "c", 0,
+		"critical threshold of locally changed items")
+	localChangesCmd.Flags().IntVar(&localChangedFiles, "files", 5,
+		"output paths of that many changed items of every folder")
+	localChangesCmd.Flags().StringArrayVar(&excludeFolders, "exclude-folder",
+		[]string{}, "IDs of folders to exclude")
+}
+
+func NewLocalChangesCheck(apiClient *client.Client) *LocalChangesCheck {
+	c := &LocalChangesCheck{
+		client:        apiClient,
+		warnThreshold: warnLocalChanges,
+		critThreshold: critLocalChanges,
+		files:         localChangedFiles,
+	}
+	return c.applyOptions()
+}
+
+type LocalChangesCheck struct {
+	client *client.Client
+	resp   *monitoringplugin.Response
+
+	excludeFolders lookupFolderId
+	warnThreshold  int
+	critThreshold  int
+	files          int
+
+	folders []*api.FolderConfiguration
+	changes []*localChanges
+}
+
+func (self *LocalChangesCheck) applyOptions() *LocalChangesCheck {
+	if self.resp == nil {
+		self.resp = monitoringplugin.NewResponse(localChangesOkMsg)
+	}
+	return self
+}
+
+func (self *LocalChangesCheck) WithExcludeFolders(folders []string,
+) *LocalChangesCheck {
+	self.excludeFolders = newLookupFolderId(folders)
+	return self
+}
+
+func (self *LocalChangesCheck) WithThresholds(warn, crit int,
+) *LocalChangesCheck {
+	self.warnThreshold = warn
+	self.critThreshold = crit
+	return self
+}
+
+// WithFiles configures how many paths of locally changed items of every folder
+// to output.
+func (self *LocalChangesCheck) WithFiles(n int) *LocalChangesCheck {
+	self.files = n
+	return self
+}
+
+func (self *LocalChangesCheck) Response() *monitoringplugin.Response {
+	return self.resp
+}
+
+func (self *LocalChangesCheck) Run(ctx context.Context) *LocalChangesCheck {
+	defer outputRetries(self.resp, self.client)
+
+	if !self.fetch(ctx) {
+		return self
+	}
+
+	self.resp.WithDefaultOkMessage(strconv.Itoa(len(self.folders)) +
+		localChangesOkMsg)
+	for i, folder := range self.folders {
+		if changes := self.changes[i]; changes != nil {
+			self.checkChanges(folder, changes)
+		}
+	}
+	self.outputExcluded()
+	return self
+}
+
+func (self *LocalChangesCheck) fetch(parentCtx context.Context) bool {
+	folders, err := self.client.Folders(parentCtx)
+	if !checkFetchError(parentCtx, self.resp, newFetchError("folders", err)) ||
+		err != nil {
+		return false
+	}
+
+	for i := range folders {
+		folder := &folders[i]
+		if folder.Type == folderTypeReceiveOnly && !folder.Paused &&
+			!self.excludeFolders.Has(folder) {
+			self.folders = append(self.folders, folder)
+		}
+	}
+
+	g, ctx := errgroup.WithContext(parentCtx)
+	g.SetLimit(fetchProcs)
+
+	self.changes = make([]*localChanges, len(self.folders))
+	for i, folder := range self.folders {
+		if ctx.Err() != nil {
+			break
+		}
+		g.Go(func() error {
+			changes, err := self.localChanges(ctx, folder)
+			self.changes[i] = changes
+			return err
+		})
+	}
+	return checkFetchError(parentCtx, self.resp, g.Wait())
+}
+
+func (self *LocalChangesCheck) localChanges(ctx context.Context,
+	folder *api.FolderConfiguration,
+) (*localChanges, error) {
+	changes := localChanges{files: make([]*api.FileInfo, 0, self.files)}
+	files := self.client.AllLocalChanged(ctx, folder.Id, localChangedPerPage)
+	for file, err := range files {
+		if err != nil {
+			return nil, newFetchError("local changes of folder "+folder.Id,
+				fmt.Errorf("local changes folder=%q: %w", folderName(folder), err))
+		}
+		if len(changes.files) < self.files {
+			changes.files = append(changes.files, file)
+		}
+		changes.total++
+	}
+	return &changes, nil
+}
+
+func (self *LocalChangesCheck) checkChanges(folder *api.FolderConfiguration,
+	changes *localChanges,
+) {
+	point := monitoringplugin.NewPerformanceDataPoint("local_changes",
+		changes.total).SetLabel(perfLabel(folder.Id))
+	if err := self.resp.AddPerformanceDataPoint(point); err != nil {
+		self.resp.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "", true)
+	}
+
+	status := self.status(changes.total)
+	if status == monitoringplugin.OK {
+		return
+	}
+
+	self.resp.UpdateStatus(status, fmt.Sprintf(
+		"folder %v: %v locally changed item(s)", folderName(folder),
+		changes.total))
+	for _, file := range changes.files {
+		if file.Deleted {
+			self.resp.UpdateStatus(status, "deleted: "+file.Name)
+		} else {
+			self.resp.UpdateStatus(status, "changed: "+file.Name)
+		}
+	}
+
+	if more := changes.total - len(changes.files); more > 0 {
+		self.resp.UpdateStatus(status, fmt.Sprintf("and %v more items", more))
+	}
+}
+
+func (self *LocalChangesCheck) status(n int) int {
+	switch {
+	case self.critThreshold > 0 && n >= self.critThreshold:
+		return monitoringplugin.CRITICAL
+	case self.warnThreshold > 0 && n >= self.warnThreshold:
+		return monitoringplugin.WARNING
+	}
+	return monitoringplugin.OK
+}
+
+func (self *LocalChangesCheck) outputExcluded() {
+	if self.excludeFolders.Excluded() {
+		self.resp.UpdateStatus(self.resp.GetStatusCode(),
+			"excluded: "+self.excludeFolders.ExcludedString())
+	}
+}
+
+// --------------------------------------------------
+
+// localChanges counts locally changed items of a folder and keeps first of
+// them.
+type localChanges struct {
+	total int
+	files []*api.FileInfo
+}
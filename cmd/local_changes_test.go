@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dsh2dsh/go-monitoringplugin/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dsh2dsh/check_syncthing/client/api"
+)
+
+func TestNewLocalChangesCheck(t *testing.T) {
+	check := testNewLocalChangesCheck(t, nil)
+	resp := check.Response()
+	require.NotNil(t, resp)
+	assert.Equal(t, "OK: "+localChangesOkMsg, resp.GetInfo().RawOutput)
+}
+
+func testNewLocalChangesCheck(t *testing.T, endpoints map[string]any,
+) *LocalChangesCheck {
+	check := NewLocalChangesCheck(newTestClient(t, fakeAPI(t, endpoints)))
+	require.NotNil(t, check)
+	return check
+}
+
+func TestLocalChangesCheck_applyOptionsWithResp(t *testing.T) {
+	resp := monitoringplugin.NewResponse("def ok msg")
+	check := &LocalChangesCheck{resp: resp}
+	require.Same(t, check, check.applyOptions())
+	assert.Same(t, resp, check.Response())
+}
+
+func TestLocalChangesCmd_PreRunE(t *testing.T) {
+	origFiles := localChangedFiles
+	t.Cleanup(func() { localChangedFiles = origFiles })
+
+	localChangedFiles = 0
+	require.NoError(t, localChangesCmd.PreRunE(&localChangesCmd, nil))
+
+	localChangedFiles = -1
+	require.ErrorContains(t, localChangesCmd.PreRunE(&localChangesCmd, nil),
+		"--files -1 is negative")
+}
+
+func TestLocalChangesCheck_Run(t *testing.T) {
+	folders := []api.FolderConfiguration{
+		{Id: "default", Label: "Default Folder", Type: "sendreceive"},
+		{Id: "backup", Label: "Backup", Type: folderTypeReceiveOnly},
+		{Id: "media", Label: "Media", Type: folderTypeReceiveOnly},
+		{
+			Id: "paused", Label: "Paused", Type: folderTypeReceiveOnly,
+			Paused: true,
+		},
+	}
+
+	const backupEP = "/rest/db/localchanged?folder=backup&page=1&perpage=1000"
+	const mediaEP = "/rest/db/localchanged?folder=media&page=1&perpage=1000"
+
+	noChanges := api.LocalChanged{Files: []api.FileInfo{}, Page: 1, Perpage: 1000}
+	changes := api.LocalChanged{
+		Files: []api.FileInfo{
+			{Name: "notes.txt", Modified: time.Now()},
+			{Name: "old.txt", Deleted: true},
+			{Name: "photo.jpg", Modified: time.Now()},
+		},
+		Page:    1,
+		Perpage: 1000,
+	}
+
+	tests := []struct {
+		name      string
+		with      func(t *testing.T, check *LocalChangesCheck)
+		endpoints map[string]any
+		expected  string
+		contains  bool
+	}{
+		{
+			name:     "without endpoints",
+			expected: "UNKNOWN: /rest/",
+			contains: true,
+		},
+		{
+			name: "without local changes",
+			endpoints: map[string]any{
+				"/rest/config/folders": folders,
+				backupEP:               noChanges,
+			},
+			expected: "UNKNOWN: /rest/",
+			contains: true,
+		},
+		{
+			name: "OK",
+			endpoints: map[string]any{
+				"/rest/config/folders": folders,
+				backupEP:               noChanges,
+				mediaEP:                noChanges,
+			},
+			expected: "OK: 2" + localChangesOkMsg +
+				" | 'local_changes_backup'=0 'local_changes_media'=0",
+		},
+		{
+			name: "warning",
+			with: func(t *testing.T, check *LocalChangesCheck) {
+				check.WithFiles(2)
+			},
+			endpoints: map[string]any{
+				"/rest/config/folders": folders,
+				backupEP:               noChanges,
+				mediaEP:                changes,
+			},
+			expected: `WARNING: folder media (Media): 3 locally changed item(s)
+changed: notes.txt
+deleted: old.txt
+and 1 more items | 'local_changes_backup'=0 'local_changes_media'=3`,
+		},
+		{
+			name: "critical",
+			with: func(t *testing.T, check *LocalChangesCheck) {
+				check.WithThresholds(0, 3).WithFiles(0)
+			},
+			endpoints: map[string]any{
+				"/rest/config/folders": folders,
+				backupEP:               changes,
+				mediaEP:                noChanges,
+			},
+			expected: `CRITICAL: folder backup (Backup): 3 locally changed item(s)
+and 3 more items | 'local_changes_backup'=3 'local_changes_media'=0`,
+		},
+		{
+			name: "excluded",
+			with: func(t *testing.T, check *LocalChangesCheck) {
+				check.WithExcludeFolders([]string{"default", "media"})
+			},
+			endpoints: map[string]any{
+				"/rest/config/folders": folders,
+				backupEP:               noChanges,
+			},
+			expected: "OK: 1" + localChangesOkMsg +
+				"\nexcluded: media (Media) | 'local_changes_backup'=0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := testNewLocalChangesCheck(t, tt.endpoints)
+			if tt.with != nil {
+				tt.with(t, check)
+			}
+			require.Same(t, check, check.Run(t.Context()))
+			rawOutput := check.Response().GetInfo().RawOutput
+			t.Log(rawOutput)
+			if tt.contains {
+				assert.Contains(t, rawOutput, tt.expected)
+			} else {
+				assert.Equal(t, tt.expected, rawOutput)
+			}
+		})
+	}
+}
+
+func TestLocalChangesCheck_Run_timeout(t *testing.T) {
+	check := testNewLocalChangesCheck(t, map[string]any{
+		"/rest/config/folders": []api.FolderConfiguration{
+			{Id: "backup", Label: "Backup", Type: folderTypeReceiveOnly},
+		},
+		"/rest/db/localchanged?folder=backup&page=1&perpage=1000": blockingAPI,
+	})
+
+	ctx := withTestTimeout(t, 50*time.Millisecond)
+	require.Same(t, check, check.Run(ctx))
+	assert.Equal(t,
+		"UNKNOWN: timed out after 50ms while fetching local changes of folder backup",
+		check.Response().GetInfo().RawOutput)
+}
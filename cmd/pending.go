@@ -0,0 +1,250 @@
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/dsh2dsh/go-monitoringplugin/v2"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/dsh2dsh/check_syncthing/client"
+	"github.com/dsh2dsh/check_syncthing/client/api"
+)
+
+const pendingOkMsg = "no pending devices or folders"
+
+var warnPending, critPending time.Duration
+
+var pendingCmd = cobra.Command{
+	Use:   "pending",
+	Short: "Check pending devices and folders",
+	Long: `Check pending devices and folders.
+
+It lists remote devices, which tried to connect, but aren't configured, and
+folders offered by remote devices, but not shared with them. It outputs warning
+or critical status for pending items older than given thresholds. Zero
+threshold disables it. Ignored devices and folders aren't pending.
+
+Outputs number of pending devices and folders as performance data.`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := checkContext(cmd)
+		defer cancel()
+		NewPendingCheck(mustAPIClient()).
+			WithExcludeDevices(excludeDevices).
+			WithThresholds(warnPending, critPending).
+			Run(ctx).Response().OutputAndExit()
+	},
+}
+
+func init() {
+	pendingCmd.Flags().DurationVarP(&warnPending, "warn", "w", 24*time.Hour,
+		"warning threshold")
+	pendingCmd.Flags().DurationVarP(&critPending, "crit", "c", 0,
+		"critical threshold")
+}
+
+func NewPendingCheck(apiClient *client.Client) *PendingCheck {
+	c := &PendingCheck{
+		client:        apiClient,
+		warnThreshold: warnPending,
+		critThreshold: critPending,
+	}
+	return c.applyOptions()
+}
+
+type PendingCheck struct {
+	client *client.Client
+	resp   *monitoringplugin.Response
+
+	excludeDevices lookupDeviceId
+	warnThreshold  time.Duration
+	critThreshold  time.Duration
+
+	devices        map[string]api.DeviceConfiguration
+	pendingDevices map[string]api.PendingDevice
+	pendingFolders map[string]api.PendingFolder
+}
+
+func (self *PendingCheck) applyOptions() *PendingCheck {
+	if self.resp == nil {
+		self.resp = monitoringplugin.NewResponse(pendingOkMsg)
+	}
+	return self
+}
+
+func (self *PendingCheck) WithExcludeDevices(devices []string) *PendingCheck {
+	self.excludeDevices = newLookupDeviceId(devices)
+	return self
+}
+
+func (self *PendingCheck) WithThresholds(warn, crit time.Duration,
+) *PendingCheck {
+	self.warnThreshold = warn
+	self.critThreshold = crit
+	return self
+}
+
+func (self *PendingCheck) Response() *monitoringplugin.Response {
+	return self.resp
+}
+
+func (self *PendingCheck) Run(ctx context.Context) *PendingCheck {
+	defer outputRetries(self.resp, self.client)
+
+	if !self.fetch(ctx) {
+		return self
+	}
+
+	devices := self.devicesItems()
+	folders := self.foldersItems()
+	if n := len(devices) + len(folders); n > 0 {
+		self.resp.WithDefaultOkMessage(strconv.Itoa(n) + " pending item(s)")
+	}
+	self.checkItems(slices.Concat(devices, folders))
+
+	self.addPerfData("pending_devices", len(devices))
+	self.addPerfData("pending_folders", len(folders))
+	self.outputExcluded()
+	return self
+}
+
+func (self *PendingCheck) fetch(parentCtx context.Context) bool {
+	g, ctx := errgroup.WithContext(parentCtx)
+	g.SetLimit(fetchProcs)
+
+	g.Go(func() error { return self.fetchDevices(ctx) })
+	g.Go(func() error { return self.fetchPendingDevices(ctx) })
+	g.Go(func() error { return self.fetchPendingFolders(ctx) })
+
+	return checkFetched(parentCtx, self.resp, g.Wait())
+}
+
+func (self *PendingCheck) fetchDevices(ctx context.Context) error {
+	devices, err := self.client.Devices(ctx)
+	if err != nil {
+		return newFetchError("devices", err)
+	}
+
+	self.devices = make(map[string]api.DeviceConfiguration, len(devices))
+	for i := range devices {
+		d := &devices[i]
+		self.devices[d.DeviceID] = devices[i]
+	}
+	return nil
+}
+
+func (self *PendingCheck) fetchPendingDevices(ctx context.Context) error {
+	devices, err := self.client.PendingDevices(ctx)
+	if err != nil {
+		return newFetchError("pending devices", err)
+	}
+	self.pendingDevices = devices
+	return nil
+}
+
+func (self *PendingCheck) fetchPendingFolders(ctx context.Context) error {
+	folders, err := self.client.PendingFolders(ctx)
+	if err != nil {
+		return newFetchError("pending folders", err)
+	}
+	self.pendingFolders = folders
+	return nil
+}
+
+func (self *PendingCheck) devicesItems() []pendingItem {
+	items := make([]pendingItem, 0, len(self.pendingDevices))
+	// Sorted, so excluded devices are outputted in stable order.
+	for _, id := range slices.Sorted(maps.Keys(self.pendingDevices)) {
+		device := self.pendingDevices[id]
+		if self.excludeDevices.Has(id) {
+			continue
+		}
+		items = append(items, pendingItem{
+			msg: "device " + deviceName(id, device.Name) + " at " +
+				device.Address,
+			since: device.Time,
+		})
+	}
+	return items
+}
+
+func (self *PendingCheck) foldersItems() []pendingItem {
+	var items []pendingItem
+	for _, folderId := range slices.Sorted(maps.Keys(self.pendingFolders)) {
+		offeredBy := self.pendingFolders[folderId].OfferedBy
+		for _, deviceId := range slices.Sorted(maps.Keys(offeredBy)) {
+			offer := offeredBy[deviceId]
+			if self.excludeDevices.Has(deviceId) {
+				continue
+			}
+			items = append(items, pendingItem{
+				msg: "folder " + folderId + " (" + offer.Label + ") offered by " +
+					self.deviceName(deviceId),
+				since: offer.Time,
+			})
+		}
+	}
+	return items
+}
+
+func (self *PendingCheck) deviceName(id string) string {
+	return deviceName(id, self.devices[id].Name)
+}
+
+// checkItems outputs items, oldest first, with status of their age.
+func (self *PendingCheck) checkItems(items []pendingItem) {
+	slices.SortFunc(items, func(a, b pendingItem) int {
+		return cmp.Or(a.since.Compare(b.since), cmp.Compare(a.msg, b.msg))
+	})
+
+	for i := range items {
+		item := &items[i]
+		self.resp.UpdateStatus(self.status(time.Since(item.since)),
+			item.msg+" pending since "+item.since.Format(time.DateTime))
+	}
+}
+
+func (self *PendingCheck) status(age time.Duration) int {
+	switch {
+	case self.critThreshold > 0 && age >= self.critThreshold:
+		return monitoringplugin.CRITICAL
+	case self.warnThreshold > 0 && age >= self.warnThreshold:
+		return monitoringplugin.WARNING
+	}
+	return monitoringplugin.OK
+}
+
+func (self *PendingCheck) addPerfData(metric string, n int) {
+	point := monitoringplugin.NewPerformanceDataPoint(metric, n)
+	if err := self.resp.AddPerformanceDataPoint(point); err != nil {
+		self.resp.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "", true)
+	}
+}
+
+func (self *PendingCheck) outputExcluded() {
+	if !self.excludeDevices.Excluded() {
+		return
+	}
+
+	// Pending devices aren't configured, but they have names too.
+	devices := maps.Clone(self.devices)
+	for id, device := range self.pendingDevices {
+		if _, ok := devices[id]; !ok {
+			devices[id] = api.DeviceConfiguration{DeviceID: id, Name: device.Name}
+		}
+	}
+	self.resp.UpdateStatus(self.resp.GetStatusCode(),
+		"excluded: "+self.excludeDevices.ExcludedString(devices))
+}
+
+// pendingItem is a pending device or folder.
+type pendingItem struct {
+	msg   string
+	since time.Time
+}
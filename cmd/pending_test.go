@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dsh2dsh/go-monitoringplugin/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dsh2dsh/check_syncthing/client/api"
+)
+
+func TestNewPendingCheck(t *testing.T) {
+	check := testNewPendingCheck(t, nil)
+	resp := check.Response()
+	require.NotNil(t, resp)
+	assert.Equal(t, "OK: "+pendingOkMsg, resp.GetInfo().RawOutput)
+}
+
+func testNewPendingCheck(t *testing.T, endpoints map[string]any,
+) *PendingCheck {
+	check := NewPendingCheck(newTestClient(t, fakeAPI(t, endpoints)))
+	require.NotNil(t, check)
+	return check
+}
+
+func TestPendingCheck_applyOptionsWithResp(t *testing.T) {
+	resp := monitoringplugin.NewResponse("def ok msg")
+	check := &PendingCheck{resp: resp}
+	require.Same(t, check, check.applyOptions())
+	assert.Same(t, resp, check.Response())
+}
+
+func TestPendingCheck_Run(t *testing.T) {
+	const testId2 = "XXXXXX2-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX"
+	const testId3 = "XXXXXX3-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX"
+	const testId5 = "XXXXXX5-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX"
+	const testId6 = "XXXXXX6-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX"
+
+	old := time.Date(2024, 3, 28, 20, 15, 11, 0, time.UTC)
+	recent := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+
+	devices := []api.DeviceConfiguration{
+		{DeviceID: testId2, Name: "nas"},
+		{
+			DeviceID: testId3,
+			Name:     "pc3",
+			IgnoredFolders: []api.ObservedFolder{
+				{Id: "photos", Label: "Photos", Time: old},
+			},
+		},
+	}
+
+	pendingDevices := map[string]api.PendingDevice{
+		testId5: {Name: "stranger", Address: "203.0.113.1:22000", Time: recent},
+	}
+
+	pendingFolders := map[string]api.PendingFolder{
+		"docs": {
+			OfferedBy: map[string]api.PendingFolderOffer{
+				testId2: {Label: "Docs", Time: old},
+			},
+		},
+		"photos": {
+			OfferedBy: map[string]api.PendingFolderOffer{
+				testId3: {Label: "Photos", Time: old.Add(time.Hour)},
+			},
+		},
+	}
+
+	const perfData = " | 'pending_devices'=1 'pending_folders'=2"
+	recentMsg := "device XXXXXX5 (stranger) at 203.0.113.1:22000 pending since " +
+		recent.Format(time.DateTime)
+
+	tests := []struct {
+		name      string
+		with      func(t *testing.T, check *PendingCheck)
+		endpoints map[string]any
+		expected  string
+	}{
+		{
+			name: "OK",
+			endpoints: map[string]any{
+				"/rest/config/devices":          devices,
+				"/rest/cluster/pending/devices": map[string]api.PendingDevice{},
+				"/rest/cluster/pending/folders": map[string]api.PendingFolder{},
+			},
+			expected: "OK: " + pendingOkMsg +
+				" | 'pending_devices'=0 'pending_folders'=0",
+		},
+		{
+			name: "OK recent",
+			endpoints: map[string]any{
+				"/rest/config/devices":          devices,
+				"/rest/cluster/pending/devices": pendingDevices,
+				"/rest/cluster/pending/folders": map[string]api.PendingFolder{},
+			},
+			expected: "OK: 1 pending item(s)\n" + recentMsg +
+				" | 'pending_devices'=1 'pending_folders'=0",
+		},
+		{
+			name: "warning",
+			endpoints: map[string]any{
+				"/rest/config/devices":          devices,
+				"/rest/cluster/pending/devices": pendingDevices,
+				"/rest/cluster/pending/folders": pendingFolders,
+			},
+			expected: `WARNING: folder docs (Docs) offered by XXXXXX2 (nas) pending since 2024-03-28 20:15:11
+folder photos (Photos) offered by XXXXXX3 (pc3) pending since 2024-03-28 21:15:11
+` + recentMsg + perfData,
+		},
+		{
+			name: "critical",
+			with: func(t *testing.T, check *PendingCheck) {
+				check.WithThresholds(30*time.Minute, 48*time.Hour)
+			},
+			endpoints: map[string]any{
+				"/rest/config/devices":          devices,
+				"/rest/cluster/pending/devices": pendingDevices,
+				"/rest/cluster/pending/folders": pendingFolders,
+			},
+			expected: `CRITICAL: folder docs (Docs) offered by XXXXXX2 (nas) pending since 2024-03-28 20:15:11
+folder photos (Photos) offered by XXXXXX3 (pc3) pending since 2024-03-28 21:15:11
+` + recentMsg + perfData,
+		},
+		{
+			name: "excluded",
+			with: func(t *testing.T, check *PendingCheck) {
+				check.WithExcludeDevices([]string{"XXXXXX2", "XXXXXX5", "XXXXXX6"})
+			},
+			endpoints: map[string]any{
+				"/rest/config/devices": devices,
+				"/rest/cluster/pending/devices": map[string]api.PendingDevice{
+					testId5: pendingDevices[testId5],
+					testId6: {Address: "203.0.113.2:22000", Time: recent},
+				},
+				"/rest/cluster/pending/folders": pendingFolders,
+			},
+			expected: `WARNING: folder photos (Photos) offered by XXXXXX3 (pc3) pending since 2024-03-28 21:15:11
+excluded: XXXXXX5 (stranger), XXXXXX6, XXXXXX2 (nas) | 'pending_devices'=0 'pending_folders'=1`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := testNewPendingCheck(t, tt.endpoints)
+			if tt.with != nil {
+				tt.with(t, check)
+			}
+			require.Same(t, check, check.Run(t.Context()))
+			rawOutput := check.Response().GetInfo().RawOutput
+			t.Log(rawOutput)
+			assert.Equal(t, tt.expected, rawOutput)
+		})
+	}
+}
+
+func TestPendingCheck_Run_timeout(t *testing.T) {
+	check := testNewPendingCheck(t, map[string]any{
+		"/rest/config/devices":          []api.DeviceConfiguration{},
+		"/rest/cluster/pending/devices": map[string]api.PendingDevice{},
+		"/rest/cluster/pending/folders": blockingAPI,
+	})
+
+	ctx := withTestTimeout(t, 50*time.Millisecond)
+	require.Same(t, check, check.Run(ctx))
+	assert.Equal(t,
+		"UNKNOWN: timed out after 50ms while fetching pending folders",
+		check.Response().GetInfo().RawOutput)
+}
@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/dsh2dsh/go-monitoringplugin/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/dsh2dsh/check_syncthing/client"
+	"github.com/dsh2dsh/check_syncthing/client/api"
+)
+
+const resourcesOkMsg = "sys: " // sys: 131M, alloc: 33.6M, ...
+
+var (
+	warnSys, critSys               uint64
+	warnAlloc, critAlloc           uint64
+	warnGoroutines, critGoroutines int
+	minUptime                      time.Duration
+)
+
+var resourcesCmd = cobra.Command{
+	Use:   "resources",
+	Short: "Check resources used by syncthing server",
+	Long: `Check resources used by syncthing server.
+
+It outputs warning or critical status, if memory obtained from OS (sys),
+allocated heap memory (alloc) or number of goroutines are out of given
+thresholds. Zero threshold disables it. Memory thresholds are given in bytes,
+or with K, M, G, T suffix, like 512M.
+
+It outputs warning status, if uptime is less than --min-uptime, because
+syncthing restarted recently.
+
+Outputs all of them as performance data.`,
+
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := validMaxThresholds("sys", warnSys, critSys); err != nil {
+			return err
+		} else if err := validMaxThresholds("alloc", warnAlloc,
+			critAlloc); err != nil {
+			return err
+		} else if err := validMaxThresholds("goroutines", warnGoroutines,
+			critGoroutines); err != nil {
+			return err
+		} else if minUptime < 0 {
+			return fmt.Errorf("--min-uptime %v is negative", minUptime)
+		}
+		return nil
+	},
+
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := checkContext(cmd)
+		defer cancel()
+		NewResourcesCheck(mustAPIClient()).
+			WithSysThresholds(warnSys, critSys).
+			WithAllocThresholds(warnAlloc, critAlloc).
+			WithGoroutinesThresholds(warnGoroutines, critGoroutines).
+			WithMinUptime(minUptime).
+			Run(ctx).Response().OutputAndExit()
+	},
+}
+
+func init() {
+	resourcesCmd.Flags().Var(newSizeValue(0, &warnSys), "warn-sys",
+		"warning threshold of memory obtained from OS")
+	resourcesCmd.Flags().Var(newSizeValue(0, &critSys), "crit-sys",
+		"critical threshold of memory obtained from OS")
+	resourcesCmd.Flags().Var(newSizeValue(0, &warnAlloc), "warn-alloc",
+		"warning threshold of allocated heap memory")
+	resourcesCmd.Flags().Var(newSizeValue(0, &critAlloc), "crit-alloc",
+		"critical threshold of allocated heap memory")
+	resourcesCmd.Flags().IntVar(&warnGoroutines, "warn-goroutines", 0,
+		"warning threshold of number of goroutines")
+	resourcesCmd.Flags().IntVar(&critGoroutines, "crit-goroutines", 0,
+		"critical threshold of number of goroutines")
+	resourcesCmd.Flags().DurationVar(&minUptime, "min-uptime", 0,
+		"warning if uptime is less than that")
+}
+
+func NewResourcesCheck(apiClient *client.Client) *ResourcesCheck {
+	c := &ResourcesCheck{
+		client: apiClient,
+
+		warnSys:        warnSys,
+		critSys:        critSys,
+		warnAlloc:      warnAlloc,
+		critAlloc:      critAlloc,
+		warnGoroutines: warnGoroutines,
+		critGoroutines: critGoroutines,
+		minUptime:      minUptime,
+	}
+	return c.applyOptions()
+}
+
+type ResourcesCheck struct {
+	client *client.Client
+	resp   *monitoringplugin.Response
+
+	warnSys, critSys               uint64
+	warnAlloc, critAlloc           uint64
+	warnGoroutines, critGoroutines int
+	minUptime                      time.Duration
+
+	system *api.SystemStatus
+}
+
+func (self *ResourcesCheck) applyOptions() *ResourcesCheck {
+	if self.resp == nil {
+		self.resp = monitoringplugin.NewResponse(resourcesOkMsg)
+	}
+	return self
+}
+
+func (self *ResourcesCheck) WithSysThresholds(warn, crit uint64,
+) *ResourcesCheck {
+	self.warnSys, self.critSys = warn, crit
+	return self
+}
+
+func (self *ResourcesCheck) WithAllocThresholds(warn, crit uint64,
+) *ResourcesCheck {
+	self.warnAlloc, self.critAlloc = warn, crit
+	return self
+}
+
+func (self *ResourcesCheck) WithGoroutinesThresholds(warn, crit int,
+) *ResourcesCheck {
+	self.warnGoroutines, self.critGoroutines = warn, crit
+	return self
+}
+
+func (self *ResourcesCheck) WithMinUptime(d time.Duration) *ResourcesCheck {
+	self.minUptime = d
+	return self
+}
+
+func (self *ResourcesCheck) Response() *monitoringplugin.Response {
+	return self.resp
+}
+
+func (self *ResourcesCheck) Run(ctx context.Context) *ResourcesCheck {
+	defer outputRetries(self.resp, self.client)
+
+	system, err := self.client.SystemStatus(ctx)
+	if !checkFetchError(ctx, self.resp, newFetchError("system status", err)) ||
+		err != nil {
+		return self
+	}
+	self.system = system
+
+	self.checkUptime()
+	self.addPerfData()
+
+	summary := self.summary()
+	if self.resp.GetStatusCode() == monitoringplugin.OK {
+		self.resp.WithDefaultOkMessage(summary)
+	} else {
+		self.resp.UpdateStatus(self.resp.GetStatusCode(), summary)
+	}
+	return self
+}
+
+func (self *ResourcesCheck) checkUptime() {
+	uptime := time.Duration(self.system.Uptime) * time.Second
+	if uptime < self.minUptime {
+		self.resp.UpdateStatus(monitoringplugin.WARNING,
+			"restarted "+uptime.String()+" ago")
+	}
+}
+
+func (self *ResourcesCheck) addPerfData() {
+	sys := monitoringplugin.NewPerformanceDataPoint("sys", self.system.Sys).
+		SetUnit("B")
+	setMaxThresholds(sys, self.warnSys, self.critSys)
+	if err := self.resp.AddPerformanceDataPoint(sys); err != nil {
+		self.resp.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "", true)
+	}
+
+	alloc := monitoringplugin.NewPerformanceDataPoint("alloc",
+		self.system.Alloc).SetUnit("B")
+	setMaxThresholds(alloc, self.warnAlloc, self.critAlloc)
+	if err := self.resp.AddPerformanceDataPoint(alloc); err != nil {
+		self.resp.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "", true)
+	}
+
+	goroutines := monitoringplugin.NewPerformanceDataPoint("goroutines",
+		self.system.Goroutines)
+	setMaxThresholds(goroutines, self.warnGoroutines, self.critGoroutines)
+	if err := self.resp.AddPerformanceDataPoint(goroutines); err != nil {
+		self.resp.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "", true)
+	}
+
+	uptime := monitoringplugin.NewPerformanceDataPoint("uptime",
+		self.system.Uptime).SetUnit("s")
+	if err := self.resp.AddPerformanceDataPoint(uptime); err != nil {
+		self.resp.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "", true)
+	}
+}
+
+func (self *ResourcesCheck) summary() string {
+	uptime := time.Duration(self.system.Uptime) * time.Second
+	return resourcesOkMsg + formatSize(self.system.Sys) +
+		", alloc: " + formatSize(self.system.Alloc) +
+		", goroutines: " + strconv.Itoa(self.system.Goroutines) +
+		", uptime: " + uptime.String()
+}
+
+// validMaxThresholds returns error, if warning or critical threshold of name is
+// negative, or warning threshold is more than critical one. Zero threshold
+// isn't used.
+func validMaxThresholds[T uint64 | int](name string, warn, crit T) error {
+	switch {
+	case warn < 0:
+		return fmt.Errorf("--warn-%v %v is negative", name, warn)
+	case crit < 0:
+		return fmt.Errorf("--crit-%v %v is negative", name, crit)
+	case warn != 0 && crit != 0 && warn > crit:
+		return fmt.Errorf("--warn-%v %v is more than --crit-%v %v", name, warn,
+			name, crit)
+	}
+	return nil
+}
+
+// setMaxThresholds sets upper warning and critical thresholds of point. Zero
+// threshold isn't used.
+func setMaxThresholds[T uint64 | int](
+	point *monitoringplugin.PerformanceDataPoint[T], warn, crit T,
+) {
+	if warn == 0 && crit == 0 {
+		return
+	}
+	point.NewThresholds(0, warn, 0, crit).
+		UseWarning(warn != 0, warn != 0).UseCritical(crit != 0, crit != 0)
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dsh2dsh/go-monitoringplugin/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dsh2dsh/check_syncthing/client/api"
+)
+
+func TestNewResourcesCheck(t *testing.T) {
+	check := testNewResourcesCheck(t, nil)
+	resp := check.Response()
+	require.NotNil(t, resp)
+	assert.Equal(t, "OK: "+resourcesOkMsg, resp.GetInfo().RawOutput)
+}
+
+func testNewResourcesCheck(t *testing.T, endpoints map[string]any,
+) *ResourcesCheck {
+	check := NewResourcesCheck(newTestClient(t, fakeAPI(t, endpoints)))
+	require.NotNil(t, check)
+	return check
+}
+
+func TestResourcesCmd_PreRunE(t *testing.T) {
+	origSys, origGoroutines := [...]uint64{warnSys, critSys},
+		[...]int{warnGoroutines, critGoroutines}
+	origUptime := minUptime
+	t.Cleanup(func() {
+		warnSys, critSys = origSys[0], origSys[1]
+		warnGoroutines, critGoroutines = origGoroutines[0], origGoroutines[1]
+		minUptime = origUptime
+	})
+
+	warnSys, critSys, warnGoroutines, critGoroutines = 100, 200, 0, 10
+	minUptime = time.Hour
+	require.NoError(t, resourcesCmd.PreRunE(&resourcesCmd, nil))
+
+	warnSys = 300
+	require.ErrorContains(t, resourcesCmd.PreRunE(&resourcesCmd, nil),
+		"--warn-sys 300 is more than --crit-sys 200")
+
+	warnSys, critGoroutines = 0, -1
+	require.ErrorContains(t, resourcesCmd.PreRunE(&resourcesCmd, nil),
+		"--crit-goroutines -1 is negative")
+
+	critGoroutines, minUptime = 0, -time.Hour
+	require.ErrorContains(t, resourcesCmd.PreRunE(&resourcesCmd, nil),
+		"--min-uptime -1h0m0s is negative")
+}
+
+func TestResourcesCheck_applyOptionsWithResp(t *testing.T) {
+	resp := monitoringplugin.NewResponse("def ok msg")
+	check := &ResourcesCheck{resp: resp}
+	require.Same(t, check, check.applyOptions())
+	assert.Same(t, resp, check.Response())
+}
+
+func TestResourcesCheck_Run(t *testing.T) {
+	system := api.SystemStatus{
+		Alloc:      35216400,
+		Goroutines: 151,
+		Sys:        137501992,
+		Uptime:     1333881,
+	}
+	const summary = "sys: 131.1M, alloc: 33.6M, goroutines: 151, " +
+		"uptime: 370h31m21s"
+
+	tests := []struct {
+		name     string
+		with     func(t *testing.T, check *ResourcesCheck)
+		system   api.SystemStatus
+		expected string
+	}{
+		{
+			name:   "OK",
+			system: system,
+			expected: "OK: " + summary + " | 'sys'=137501992B " +
+				"'alloc'=35216400B 'goroutines'=151 'uptime'=1333881s",
+		},
+		{
+			name: "OK with thresholds",
+			with: func(t *testing.T, check *ResourcesCheck) {
+				check.WithSysThresholds(256<<20, 0).
+					WithAllocThresholds(0, 512<<20).
+					WithGoroutinesThresholds(1000, 2000).
+					WithMinUptime(time.Hour)
+			},
+			system: system,
+			expected: "OK: " + summary + " | 'sys'=137501992B;268435456;;; " +
+				"'alloc'=35216400B;;536870912;; 'goroutines'=151;1000;2000;; " +
+				"'uptime'=1333881s",
+		},
+		{
+			name: "out of thresholds",
+			with: func(t *testing.T, check *ResourcesCheck) {
+				check.WithSysThresholds(64<<20, 128<<20).
+					WithGoroutinesThresholds(100, 0)
+			},
+			system: system,
+			expected: `CRITICAL: sys is outside of CRITICAL threshold
+` + summary + `
+goroutines is outside of WARNING threshold | 'sys'=137501992B;67108864;134217728;; ` +
+				"'alloc'=35216400B 'goroutines'=151;100;;; 'uptime'=1333881s",
+		},
+		{
+			name: "restarted",
+			with: func(t *testing.T, check *ResourcesCheck) {
+				check.WithMinUptime(10 * time.Minute)
+			},
+			system: api.SystemStatus{
+				Alloc: 1 << 20, Goroutines: 10, Sys: 2 << 20, Uptime: 65,
+			},
+			expected: `WARNING: restarted 1m5s ago
+sys: 2M, alloc: 1M, goroutines: 10, uptime: 1m5s | 'sys'=2097152B 'alloc'=1048576B 'goroutines'=10 'uptime'=65s`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := testNewResourcesCheck(t, map[string]any{
+				"/rest/system/status": tt.system,
+			})
+			if tt.with != nil {
+				tt.with(t, check)
+			}
+			require.Same(t, check, check.Run(t.Context()))
+			rawOutput := check.Response().GetInfo().RawOutput
+			t.Log(rawOutput)
+			assert.Equal(t, tt.expected, rawOutput)
+		})
+	}
+}
+
+func TestResourcesCheck_Run_timeout(t *testing.T) {
+	check := testNewResourcesCheck(t, map[string]any{
+		"/rest/system/status": blockingAPI,
+	})
+
+	ctx := withTestTimeout(t, 50*time.Millisecond)
+	require.Same(t, check, check.Run(ctx))
+	assert.Equal(t,
+		"UNKNOWN: timed out after 50ms while fetching system status",
+		check.Response().GetInfo().RawOutput)
+}
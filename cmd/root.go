@@ -3,6 +3,7 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"log"
 	"net/url"
 	"os"
 	"strings"
@@ -20,6 +21,13 @@ const fetchProcs = 8
 var (
 	apiKey, baseURL string
 	excludeDevices  []string
+	excludeFolders  []string
+
+	caCert, clientCert, clientKey, fingerprint string
+	insecure                                   bool
+
+	debug    bool
+	debugLog = log.New(os.Stderr, "debug: ", 0)
 
 	rootCmd = cobra.Command{
 		Use:   "check_syncthing",
@@ -28,7 +36,23 @@ var (
 
 Requires server URL and API key using flags or environment variables
 SYNCTHING_API_KEY and SYNCTHING_URL. Environment variables can be configured
-inside .env file in current dir.`,
+inside .env file in current dir. API key can be read from a file or a command,
+using flags or environment variables SYNCTHING_API_KEY_FILE and
+SYNCTHING_API_KEY_COMMAND. Flags take precedence over environment variables.
+API key file must not be world-readable. Server URL can be
+unix:///path/gui.sock or unixs:///path/gui.sock, if syncthing GUI listens on
+unix socket.
+
+If server URL or API key isn't configured, they are read from syncthing
+config.xml, given by --config-xml flag or SYNCTHING_CONFIG_XML environment
+variable. By default it looks for config.xml in
+~/.local/state/syncthing/config.xml and ~/.config/syncthing/config.xml. API key
+is read from config.xml only if server URL is read from it too, or server URL
+is a loopback address or unix socket.
+
+TLS flags can be configured using environment variables SYNCTHING_CA_CERT,
+SYNCTHING_CLIENT_CERT, SYNCTHING_CLIENT_KEY, SYNCTHING_FINGERPRINT and
+SYNCTHING_INSECURE.`,
 
 		PersistentPreRunE: persistentPreRunE,
 	}
@@ -39,10 +63,37 @@ func init() {
 		[]string{}, "short IDs of devices to exclude")
 	rootCmd.PersistentFlags().StringVarP(&apiKey, "key", "k", "",
 		"syncthing REST API key")
-	rootCmd.PersistentFlags().StringVarP(&baseURL, "url", "u", "", "server URL")
+	rootCmd.PersistentFlags().StringVarP(&baseURL, "url", "u", "",
+		"server URL, like http://127.0.0.1:8384 or unix:///path/gui.sock")
+
+	rootCmd.PersistentFlags().StringVar(&caCert, "ca-cert", "",
+		"PEM file with CA certificates for verifying server certificate")
+	rootCmd.PersistentFlags().StringVar(&clientCert, "client-cert", "",
+		"PEM file with client certificate")
+	rootCmd.PersistentFlags().StringVar(&clientKey, "client-key", "",
+		"PEM file with private key of client certificate")
+	rootCmd.PersistentFlags().StringVar(&fingerprint, "fingerprint", "",
+		"SHA-256 fingerprint of expected server certificate")
+	rootCmd.PersistentFlags().BoolVar(&insecure, "insecure", false,
+		"don't verify server certificate")
+
+	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false,
+		"output debug messages to stderr")
+
+	rootCmd.AddCommand(&connectionsCmd)
+	rootCmd.AddCommand(&dbStatusCmd)
+	rootCmd.AddCommand(&deletionsCmd)
+	rootCmd.AddCommand(&discoveryCmd)
+	rootCmd.AddCommand(&eventsCmd)
 	rootCmd.AddCommand(&foldersCmd)
 	rootCmd.AddCommand(&healthCmd)
 	rootCmd.AddCommand(&lastSeenCmd)
+	rootCmd.AddCommand(&listenersCmd)
+	rootCmd.AddCommand(&localChangesCmd)
+	rootCmd.AddCommand(&pendingCmd)
+	rootCmd.AddCommand(&resourcesCmd)
+	rootCmd.AddCommand(&scanAgeCmd)
+	rootCmd.AddCommand(&versionCmd)
 }
 
 func Execute(version string) {
@@ -52,33 +103,96 @@ func Execute(version string) {
 	}
 }
 
-func persistentPreRunE(cmd *cobra.Command, args []string) error {
+func persistentPreRunE(cmd *cobra.Command, args []string) (err error) {
 	// Don't show usage on app errors.
 	// https://github.com/spf13/cobra/issues/340#issuecomment-378726225
 	cmd.SilenceUsage = true
 
+	// --key-command is bounded by --timeout too. The check reuses the same
+	// context, so it isn't canceled here on success.
+	cancel := setCheckContext(cmd)
+	defer func() {
+		if err != nil {
+			cancel()
+		}
+	}()
+
 	if err := loadEnvs(); err != nil {
 		return err
+	} else if err := loadAPIKey(cmd.Context()); err != nil {
+		return err
+	} else if err := loadConfigXML(); err != nil {
+		return err
+	}
+
+	if apiKey != "" {
+		debugf("API key from %v", apiKeySource)
+	} else {
+		debugf("no API key configured")
 	}
 	return rootValidate()
 }
 
+func debugf(format string, v ...any) {
+	if debug {
+		debugLog.Printf(format, v...)
+	}
+}
+
 func loadEnvs() error {
 	cfg := struct {
-		Key string `env:"SYNCTHING_API_KEY"`
-		URL string `env:"SYNCTHING_URL"`
+		Key        string `env:"SYNCTHING_API_KEY"`
+		KeyFile    string `env:"SYNCTHING_API_KEY_FILE"`
+		KeyCommand string `env:"SYNCTHING_API_KEY_COMMAND"`
+		URL        string `env:"SYNCTHING_URL"`
+		ConfigXML  string `env:"SYNCTHING_CONFIG_XML"`
+
+		CACert      string `env:"SYNCTHING_CA_CERT"`
+		ClientCert  string `env:"SYNCTHING_CLIENT_CERT"`
+		ClientKey   string `env:"SYNCTHING_CLIENT_KEY"`
+		Fingerprint string `env:"SYNCTHING_FINGERPRINT"`
+		Insecure    bool   `env:"SYNCTHING_INSECURE"`
 	}{}
 	err := dotenv.New().WithDepth(1).Load(func() error { return env.Parse(&cfg) })
 	if err != nil {
 		return fmt.Errorf("load .env: %w", err)
 	}
 
-	if apiKey == "" {
-		apiKey = cfg.Key
+	// --key-file and --key-command flags win over SYNCTHING_API_KEY.
+	if apiKey != "" {
+		apiKeySource = "--key flag"
+	} else if cfg.Key != "" && keyFile == "" && keyCommand == "" {
+		apiKey, apiKeySource = cfg.Key, "SYNCTHING_API_KEY"
 	}
+	if keyFile == "" {
+		keyFile = cfg.KeyFile
+	}
+	if keyCommand == "" {
+		keyCommand = cfg.KeyCommand
+	}
+
 	if baseURL == "" {
 		baseURL = cfg.URL
 	}
+	if configXML == "" {
+		configXML = cfg.ConfigXML
+	}
+
+	if caCert == "" {
+		caCert = cfg.CACert
+	}
+	if clientCert == "" {
+		clientCert = cfg.ClientCert
+	}
+	if clientKey == "" {
+		clientKey = cfg.ClientKey
+	}
+	if fingerprint == "" {
+		fingerprint = cfg.Fingerprint
+	}
+	if !insecure {
+		insecure = cfg.Insecure
+	}
 	return nil
 }
 
@@ -93,22 +207,74 @@ func rootValidate() error {
 	} else if !u.IsAbs() {
 		return fmt.Errorf("url %q not absolute", u.String())
 	}
+
+	switch u.Scheme {
+	case "http", "https":
+		if u.Host == "" {
+			return fmt.Errorf("url %q without host", u.String())
+		}
+	case "unix", "unixs":
+		if u.Path == "" {
+			return fmt.Errorf("url %q without socket path", u.String())
+		}
+	default:
+		return fmt.Errorf("url %q has unsupported scheme", u.String())
+	}
 	baseURL = u.String()
+
+	if (clientCert == "") != (clientKey == "") {
+		return errors.New("client certificate requires both cert and key")
+	} else if retries < 0 {
+		return fmt.Errorf("negative number of retries: %v", retries)
+	} else if retries > maxRetries {
+		return fmt.Errorf("number of retries %v is more than %v", retries,
+			maxRetries)
+	}
 	return nil
 }
 
 func mustAPIClient() *client.Client {
-	c, err := client.New(baseURL)
+	c, err := client.New(baseURL, clientOptions()...)
 	if err != nil {
 		cobra.CheckErr(fmt.Errorf("create syncthing REST API client: %w", err))
 	}
 	return c.WithKey(apiKey)
 }
 
+func clientOptions() []client.Option {
+	var opts []client.Option
+	if caCert != "" {
+		opts = append(opts, client.WithCACert(caCert))
+	}
+	if clientCert != "" {
+		opts = append(opts, client.WithClientCert(clientCert, clientKey))
+	}
+	if fingerprint != "" {
+		opts = append(opts, client.WithFingerprint(fingerprint))
+	}
+	if insecure {
+		opts = append(opts, client.WithInsecureSkipVerify())
+	}
+	if retries > 0 {
+		opts = append(opts, client.WithRetry(retries, retryDelay))
+	}
+	if checkTimeout > 0 {
+		// Requests are bounded by --timeout of the check instead.
+		opts = append(opts, client.WithTimeout(0))
+	}
+	return opts
+}
+
 // --------------------------------------------------
 
+// deviceName returns short ID of device with its name, or short ID only, if
+// name is empty.
 func deviceName(id, name string) string {
-	return newDeviceId(id).Short() + " (" + name + ")"
+	shortId := newDeviceId(id).Short()
+	if name == "" {
+		return shortId
+	}
+	return shortId + " (" + name + ")"
 }
 
 func newDeviceId(id string) deviceId {
@@ -122,6 +288,12 @@ func (self deviceId) Short() string {
 	return shortId
 }
 
+// perfLabel returns s usable as label of performance data point, which can't
+// contain ' and =.
+func perfLabel(s string) string {
+	return strings.NewReplacer("'", "_", "=", "_").Replace(s)
+}
+
 // --------------------------------------------------
 
 func newLookupDeviceId(ids []string) lookupDeviceId {
@@ -168,3 +340,35 @@ func (self *lookupDeviceId) ExcludedString(
 	}
 	return strings.Join(excluded, ", ")
 }
+
+// --------------------------------------------------
+
+func newLookupFolderId(ids []string) lookupFolderId {
+	l := lookupFolderId{folders: make(map[string]bool, len(ids))}
+	for _, id := range ids {
+		l.folders[id] = false
+	}
+	return l
+}
+
+type lookupFolderId struct {
+	folders  map[string]bool
+	excluded []string
+}
+
+func (self *lookupFolderId) Has(folder *api.FolderConfiguration) bool {
+	seen, ok := self.folders[folder.Id]
+	if ok && !seen {
+		self.excluded = append(self.excluded, folderName(folder))
+		self.folders[folder.Id] = true
+	}
+	return ok
+}
+
+func (self *lookupFolderId) Excluded() bool {
+	return len(self.excluded) > 0
+}
+
+func (self *lookupFolderId) ExcludedString() string {
+	return strings.Join(self.excluded, ", ")
+}
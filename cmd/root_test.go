@@ -4,6 +4,7 @@ import (
 	"os"
 	"os/exec"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -12,14 +13,16 @@ import (
 )
 
 func TestPersistentPreRunE(t *testing.T) {
-	origBaseURL := baseURL
+	origBaseURL, origCtx := baseURL, rootCmd.Context()
 	silenceUsage := rootCmd.SilenceUsage
 	t.Cleanup(func() {
 		baseURL = origBaseURL
 		rootCmd.SilenceUsage = silenceUsage
+		rootCmd.SetContext(origCtx)
 	})
 
 	baseURL = "http://127.0.0.1"
+	rootCmd.SetContext(t.Context())
 	require.NoError(t, persistentPreRunE(&rootCmd, []string{}))
 	assert.True(t, rootCmd.SilenceUsage)
 }
@@ -42,6 +45,36 @@ func TestLoadEnvs(t *testing.T) {
 	require.NoError(t, loadEnvs())
 	assert.Equal(t, "foobaz", apiKey)
 	assert.Equal(t, "/syncthing/", baseURL)
+
+	origConfigXML := configXML
+	t.Cleanup(func() { configXML = origConfigXML })
+	configXML = ""
+	t.Setenv("SYNCTHING_CONFIG_XML", "config.xml")
+	require.NoError(t, loadEnvs())
+	assert.Equal(t, "config.xml", configXML)
+}
+
+func TestLoadEnvs_tls(t *testing.T) {
+	origCACert, origClientCert, origClientKey := caCert, clientCert, clientKey
+	origFingerprint, origInsecure := fingerprint, insecure
+	t.Cleanup(func() {
+		caCert, clientCert, clientKey = origCACert, origClientCert, origClientKey
+		fingerprint, insecure = origFingerprint, origInsecure
+	})
+
+	caCert, clientCert, clientKey = "ca.pem", "", ""
+	fingerprint, insecure = "", false
+	t.Setenv("SYNCTHING_CA_CERT", "ca2.pem")
+	t.Setenv("SYNCTHING_CLIENT_CERT", "cert.pem")
+	t.Setenv("SYNCTHING_CLIENT_KEY", "key.pem")
+	t.Setenv("SYNCTHING_FINGERPRINT", "AB:CD")
+	t.Setenv("SYNCTHING_INSECURE", "true")
+	require.NoError(t, loadEnvs())
+	assert.Equal(t, "ca.pem", caCert)
+	assert.Equal(t, "cert.pem", clientCert)
+	assert.Equal(t, "key.pem", clientKey)
+	assert.Equal(t, "AB:CD", fingerprint)
+	assert.True(t, insecure)
 }
 
 func TestRootValidate(t *testing.T) {
@@ -55,8 +88,61 @@ func TestRootValidate(t *testing.T) {
 	baseURL = "/"
 	require.ErrorContains(t, rootValidate(), "not absolute")
 
+	baseURL = "ftp://127.0.0.1"
+	require.ErrorContains(t, rootValidate(), "unsupported scheme")
+
+	baseURL = "http:/foo"
+	require.ErrorContains(t, rootValidate(), "without host")
+
+	baseURL = "unix://"
+	require.ErrorContains(t, rootValidate(), "without socket path")
+
+	baseURL = "unix:///var/run/syncthing.sock"
+	require.NoError(t, rootValidate())
+
+	baseURL = "unixs:///var/run/syncthing.sock"
+	require.NoError(t, rootValidate())
+
 	baseURL = "http://127.0.0.1"
 	require.NoError(t, rootValidate())
+
+	origClientCert, origClientKey := clientCert, clientKey
+	t.Cleanup(func() { clientCert, clientKey = origClientCert, origClientKey })
+	clientCert = "cert.pem"
+	require.ErrorContains(t, rootValidate(), "requires both cert and key")
+
+	clientKey = "key.pem"
+	require.NoError(t, rootValidate())
+
+	origRetries := retries
+	t.Cleanup(func() { retries = origRetries })
+	retries = -1
+	require.ErrorContains(t, rootValidate(), "negative number of retries")
+	retries = maxRetries + 1
+	require.ErrorContains(t, rootValidate(),
+		"number of retries 11 is more than 10")
+}
+
+func TestClientOptions(t *testing.T) {
+	origCACert, origClientCert, origClientKey := caCert, clientCert, clientKey
+	origFingerprint, origInsecure, origRetries := fingerprint, insecure, retries
+	t.Cleanup(func() {
+		caCert, clientCert, clientKey = origCACert, origClientCert, origClientKey
+		fingerprint, insecure, retries = origFingerprint, origInsecure, origRetries
+	})
+
+	caCert, clientCert, clientKey = "", "", ""
+	fingerprint, insecure, retries = "", false, 0
+	assert.Empty(t, clientOptions())
+
+	caCert, clientCert, clientKey = "ca.pem", "cert.pem", "key.pem"
+	fingerprint, insecure, retries = "AB:CD", true, 2
+	assert.Len(t, clientOptions(), 5)
+
+	origTimeout := checkTimeout
+	t.Cleanup(func() { checkTimeout = origTimeout })
+	checkTimeout = time.Minute
+	assert.Len(t, clientOptions(), 6)
 }
 
 func TestMustAPIClient(t *testing.T) {
@@ -77,6 +163,11 @@ func TestDeviceName(t *testing.T) {
 	assert.Equal(t, "XXXXXX2 (system name)", name)
 }
 
+func TestPerfLabel(t *testing.T) {
+	assert.Equal(t, "foo-bar", perfLabel("foo-bar"))
+	assert.Equal(t, "foo_bar_baz", perfLabel("foo'bar=baz"))
+}
+
 func TestNewDeviceId(t *testing.T) {
 	id := newDeviceId(
 		"XXXXXX2-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXX1")
@@ -121,3 +212,23 @@ func TestNewLookupDeviceId(t *testing.T) {
 	assert.True(t, l.Has(testId4))
 	assert.Equal(t, testEx2+", XXXXXX4 (device 2)", l.ExcludedString(devices))
 }
+
+func TestNewLookupFolderId(t *testing.T) {
+	folder1 := api.FolderConfiguration{Id: "default", Label: "Default Folder"}
+	folder2 := api.FolderConfiguration{Id: "photos", Label: "Photos"}
+
+	l := lookupFolderId{}
+	assert.False(t, l.Has(&folder1))
+	assert.False(t, l.Excluded())
+	assert.Empty(t, l.ExcludedString())
+
+	l = newLookupFolderId([]string{"foo", "default"})
+	assert.False(t, l.Excluded())
+	assert.True(t, l.Has(&folder1))
+	assert.True(t, l.Excluded())
+	assert.Equal(t, "default (Default Folder)", l.ExcludedString())
+
+	assert.True(t, l.Has(&folder1))
+	assert.False(t, l.Has(&folder2))
+	assert.Equal(t, "default (Default Folder)", l.ExcludedString())
+}
@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/dsh2dsh/go-monitoringplugin/v2"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/dsh2dsh/check_syncthing/client"
+	"github.com/dsh2dsh/check_syncthing/client/api"
+)
+
+const scanAgeOkMsg = "oldest scan: " // oldest scan: 5m0s ago, folder ...
+
+// Multipliers of folder rescan interval, used as thresholds by default.
+const (
+	scanAgeWarnRescans = 2
+	scanAgeCritRescans = 4
+)
+
+var warnScanAge, critScanAge time.Duration
+
+var scanAgeCmd = cobra.Command{
+	Use:   "scan-age",
+	Short: "Check time since last scan of syncthing folders",
+	Long: `Check time since last scan of syncthing folders.
+
+It outputs warning or critical status for folders, which weren't scanned
+longer than given thresholds. By default thresholds are 2 and 4 rescan
+intervals of every folder. Folders with disabled rescans are skipped, unless
+thresholds are given explicitly. Paused folders are skipped too.
+
+Outputs seconds since last scan and since last received file of every folder
+as performance data.`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := checkContext(cmd)
+		defer cancel()
+		NewScanAgeCheck(mustAPIClient()).
+			WithExcludeFolders(excludeFolders).
+			WithThresholds(warnScanAge, critScanAge).
+			Run(ctx).Response().OutputAndExit()
+	},
+}
+
+func init() {
+	scanAgeCmd.Flags().DurationVarP(&warnScanAge, "warn", "w", 0,
+		"warning threshold (default 2 rescan intervals)")
+	scanAgeCmd.Flags().DurationVarP(&critScanAge, "crit", "c", 0,
+		"critical threshold (default 4 rescan intervals)")
+	scanAgeCmd.Flags().StringArrayVar(&excludeFolders, "exclude-folder",
+		[]string{}, "IDs of folders to exclude")
+}
+
+func NewScanAgeCheck(apiClient *client.Client) *ScanAgeCheck {
+	c := &ScanAgeCheck{
+		client:        apiClient,
+		warnThreshold: warnScanAge,
+		critThreshold: critScanAge,
+	}
+	return c.applyOptions()
+}
+
+type ScanAgeCheck struct {
+	client *client.Client
+	resp   *monitoringplugin.Response
+
+	excludeFolders lookupFolderId
+	warnThreshold  time.Duration
+	critThreshold  time.Duration
+
+	folders []api.FolderConfiguration
+	stats   map[string]api.FolderStatistics
+}
+
+func (self *ScanAgeCheck) applyOptions() *ScanAgeCheck {
+	if self.resp == nil {
+		self.resp = monitoringplugin.NewResponse(scanAgeOkMsg)
+	}
+	return self
+}
+
+func (self *ScanAgeCheck) WithExcludeFolders(folders []string) *ScanAgeCheck {
+	self.excludeFolders = newLookupFolderId(folders)
+	return self
+}
+
+// WithThresholds configures thresholds of time since last scan. Zero threshold
+// means multiple of rescan interval of every folder.
+func (self *ScanAgeCheck) WithThresholds(warn, crit time.Duration,
+) *ScanAgeCheck {
+	self.warnThreshold = warn
+	self.critThreshold = crit
+	return self
+}
+
+func (self *ScanAgeCheck) Response() *monitoringplugin.Response {
+	return self.resp
+}
+
+func (self *ScanAgeCheck) Run(ctx context.Context) *ScanAgeCheck {
+	defer outputRetries(self.resp, self.client)
+
+	if !self.fetch(ctx) {
+		return self
+	}
+
+	var oldest *api.FolderConfiguration
+	var oldestAge time.Duration
+	for i := range self.folders {
+		folder := &self.folders[i]
+		if folder.Paused || self.excludeFolders.Has(folder) {
+			continue
+		}
+
+		stat, ok := self.stats[folder.Id]
+		if !ok {
+			continue
+		}
+		age, ok := self.checkFolder(folder, &stat)
+		if ok && (oldest == nil || age > oldestAge) {
+			oldest, oldestAge = folder, age
+		}
+		self.addPerfData(folder, &stat)
+	}
+
+	if oldest != nil {
+		self.resp.WithDefaultOkMessage(scanAgeOkMsg + oldestAge.String() +
+			" ago, folder " + folderName(oldest))
+	} else {
+		self.resp.WithDefaultOkMessage("no scanned folders")
+	}
+	self.outputExcluded()
+	return self
+}
+
+func (self *ScanAgeCheck) fetch(parentCtx context.Context) bool {
+	g, ctx := errgroup.WithContext(parentCtx)
+	g.SetLimit(fetchProcs)
+
+	g.Go(func() error { return self.fetchFolders(ctx) })
+	g.Go(func() error { return self.fetchStats(ctx) })
+
+	return checkFetched(parentCtx, self.resp, g.Wait())
+}
+
+func (self *ScanAgeCheck) fetchFolders(ctx context.Context) error {
+	folders, err := self.client.Folders(ctx)
+	if err != nil {
+		return newFetchError("folders", err)
+	}
+	self.folders = folders
+	return nil
+}
+
+func (self *ScanAgeCheck) fetchStats(ctx context.Context) error {
+	stats, err := self.client.FolderStats(ctx)
+	if err != nil {
+		return newFetchError("folder stats", err)
+	}
+	self.stats = stats
+	return nil
+}
+
+// checkFolder outputs status of time since last scan of folder and returns
+// that time. It returns false, if folder was never scanned.
+func (self *ScanAgeCheck) checkFolder(folder *api.FolderConfiguration,
+	stat *api.FolderStatistics,
+) (time.Duration, bool) {
+	warn, crit := self.thresholds(folder)
+	if warn == 0 && crit == 0 {
+		return 0, false
+	} else if stat.LastScan.IsZero() {
+		self.resp.UpdateStatus(monitoringplugin.WARNING,
+			"folder "+folderName(folder)+" never scanned")
+		return 0, false
+	}
+
+	age := time.Since(stat.LastScan).Truncate(time.Second)
+	var status int
+	switch {
+	case crit > 0 && age >= crit:
+		status = monitoringplugin.CRITICAL
+	case warn > 0 && age >= warn:
+		status = monitoringplugin.WARNING
+	default:
+		return age, true
+	}
+
+	self.resp.UpdateStatus(status, "folder "+folderName(folder)+
+		" last scan "+age.String()+" ago")
+	return age, true
+}
+
+func (self *ScanAgeCheck) thresholds(folder *api.FolderConfiguration,
+) (warn, crit time.Duration) {
+	warn, crit = self.warnThreshold, self.critThreshold
+	if warn == 0 && crit == 0 {
+		rescan := time.Duration(folder.RescanIntervalS) * time.Second
+		warn, crit = scanAgeWarnRescans*rescan, scanAgeCritRescans*rescan
+	}
+	return warn, crit
+}
+
+func (self *ScanAgeCheck) addPerfData(folder *api.FolderConfiguration,
+	stat *api.FolderStatistics,
+) {
+	label := perfLabel(folder.Id)
+	if !stat.LastScan.IsZero() {
+		point := monitoringplugin.NewPerformanceDataPoint("last_scan",
+			int64(time.Since(stat.LastScan).Seconds())).
+			SetUnit("s").SetLabel(label)
+		if err := self.resp.AddPerformanceDataPoint(point); err != nil {
+			self.resp.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "", true)
+		}
+	}
+
+	if !stat.LastFile.At.IsZero() {
+		point := monitoringplugin.NewPerformanceDataPoint("last_file",
+			int64(time.Since(stat.LastFile.At).Seconds())).
+			SetUnit("s").SetLabel(label)
+		if err := self.resp.AddPerformanceDataPoint(point); err != nil {
+			self.resp.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "", true)
+		}
+	}
+}
+
+func (self *ScanAgeCheck) outputExcluded() {
+	if self.excludeFolders.Excluded() {
+		self.resp.UpdateStatus(self.resp.GetStatusCode(),
+			"excluded: "+self.excludeFolders.ExcludedString())
+	}
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dsh2dsh/go-monitoringplugin/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dsh2dsh/check_syncthing/client/api"
+)
+
+func TestNewScanAgeCheck(t *testing.T) {
+	check := testNewScanAgeCheck(t, nil)
+	resp := check.Response()
+	require.NotNil(t, resp)
+	assert.Equal(t, "OK: "+scanAgeOkMsg, resp.GetInfo().RawOutput)
+}
+
+func testNewScanAgeCheck(t *testing.T, endpoints map[string]any,
+) *ScanAgeCheck {
+	check := NewScanAgeCheck(newTestClient(t, fakeAPI(t, endpoints)))
+	require.NotNil(t, check)
+	return check
+}
+
+func TestScanAgeCheck_applyOptionsWithResp(t *testing.T) {
+	resp := monitoringplugin.NewResponse("def ok msg")
+	check := &ScanAgeCheck{resp: resp}
+	require.Same(t, check, check.applyOptions())
+	assert.Same(t, resp, check.Response())
+}
+
+func TestScanAgeCheck_Run(t *testing.T) {
+	now := time.Now()
+	folders := []api.FolderConfiguration{
+		{Id: "default", Label: "Default Folder", RescanIntervalS: 3600},
+		{Id: "photos", Label: "Photos", RescanIntervalS: 600},
+		{Id: "paused", Label: "Paused", RescanIntervalS: 60, Paused: true},
+		{Id: "manual", Label: "Manual"},
+	}
+
+	stats := map[string]api.FolderStatistics{
+		"default": {
+			LastScan: now.Add(-time.Hour),
+			LastFile: api.LastFile{At: now.Add(-2 * time.Hour), Filename: "a"},
+		},
+		"photos": {LastScan: now.Add(-5 * time.Minute)},
+		"paused": {LastScan: now.Add(-time.Hour)},
+		"manual": {LastScan: now.Add(-24 * time.Hour)},
+	}
+
+	const perfData = " | 'last_scan_default'=3600s 'last_file_default'=7200s" +
+		" 'last_scan_photos'=300s 'last_scan_manual'=86400s"
+
+	tests := []struct {
+		name      string
+		with      func(t *testing.T, check *ScanAgeCheck)
+		endpoints map[string]any
+		expected  string
+		contains  bool
+	}{
+		{
+			name:     "without endpoints",
+			expected: "UNKNOWN: /rest/",
+			contains: true,
+		},
+		{
+			name: "without stats",
+			endpoints: map[string]any{
+				"/rest/config/folders": folders,
+			},
+			expected: "UNKNOWN: /rest/",
+			contains: true,
+		},
+		{
+			name: "OK",
+			endpoints: map[string]any{
+				"/rest/config/folders": folders,
+				"/rest/stats/folder":   stats,
+			},
+			expected: "OK: oldest scan: 1h0m0s ago, folder default (Default Folder)" +
+				perfData,
+		},
+		{
+			name: "never scanned",
+			endpoints: map[string]any{
+				"/rest/config/folders": folders[:1],
+				"/rest/stats/folder": map[string]api.FolderStatistics{
+					"default": {},
+				},
+			},
+			expected: "WARNING: folder default (Default Folder) never scanned",
+		},
+		{
+			name: "warning",
+			endpoints: map[string]any{
+				"/rest/config/folders": folders,
+				"/rest/stats/folder": map[string]api.FolderStatistics{
+					"default": stats["default"],
+					"photos":  {LastScan: now.Add(-30 * time.Minute)},
+				},
+			},
+			expected: "WARNING: folder photos (Photos) last scan 30m0s ago" +
+				" | 'last_scan_default'=3600s 'last_file_default'=7200s" +
+				" 'last_scan_photos'=1800s",
+		},
+		{
+			name: "explicit thresholds",
+			with: func(t *testing.T, check *ScanAgeCheck) {
+				check.WithThresholds(30*time.Minute, 12*time.Hour)
+			},
+			endpoints: map[string]any{
+				"/rest/config/folders": folders,
+				"/rest/stats/folder":   stats,
+			},
+			expected: `CRITICAL: folder manual (Manual) last scan 24h0m0s ago
+folder default (Default Folder) last scan 1h0m0s ago` + perfData,
+		},
+		{
+			name: "excluded",
+			with: func(t *testing.T, check *ScanAgeCheck) {
+				check.WithThresholds(30*time.Minute, 0).
+					WithExcludeFolders([]string{"default", "manual"})
+			},
+			endpoints: map[string]any{
+				"/rest/config/folders": folders,
+				"/rest/stats/folder":   stats,
+			},
+			expected: "OK: oldest scan: 5m0s ago, folder photos (Photos)\n" +
+				"excluded: default (Default Folder), manual (Manual)" +
+				" | 'last_scan_photos'=300s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := testNewScanAgeCheck(t, tt.endpoints)
+			if tt.with != nil {
+				tt.with(t, check)
+			}
+			require.Same(t, check, check.Run(t.Context()))
+			rawOutput := check.Response().GetInfo().RawOutput
+			t.Log(rawOutput)
+			if tt.contains {
+				assert.Contains(t, rawOutput, tt.expected)
+			} else {
+				assert.Equal(t, tt.expected, rawOutput)
+			}
+		})
+	}
+}
+
+func TestScanAgeCheck_Run_timeout(t *testing.T) {
+	check := testNewScanAgeCheck(t, map[string]any{
+		"/rest/config/folders": []api.FolderConfiguration{},
+		"/rest/stats/folder":   blockingAPI,
+	})
+
+	ctx := withTestTimeout(t, 50*time.Millisecond)
+	require.Same(t, check, check.Run(ctx))
+	assert.Equal(t,
+		"UNKNOWN: timed out after 50ms while fetching folder stats",
+		check.Response().GetInfo().RawOutput)
+}
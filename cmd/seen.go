@@ -25,10 +25,12 @@ It lookups a syncthing client with oldest last seen time and outputs warning or
 critical status if it's out of given thresholds.`,
 
 	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := checkContext(cmd)
+		defer cancel()
 		NewLastSeenCheck(mustAPIClient()).
 			WithExcludeDevices(excludeDevices).
 			WithThresholds(warnLastSeen, critLastSeen).
-			Run().Response().OutputAndExit()
+			Run(ctx).Response().OutputAndExit()
 	},
 }
 
@@ -85,8 +87,10 @@ func (self *LastSeenCheck) Response() *monitoringplugin.Response {
 	return self.resp
 }
 
-func (self *LastSeenCheck) Run() *LastSeenCheck {
-	if !self.fetch(context.Background()) {
+func (self *LastSeenCheck) Run(ctx context.Context) *LastSeenCheck {
+	defer outputRetries(self.resp, self.client)
+
+	if !self.fetch(ctx) {
 		return self
 	}
 
@@ -123,14 +127,13 @@ func (self *LastSeenCheck) fetch(parentCtx context.Context) bool {
 	g.Go(func() error { return self.fetchDevices(ctx) })
 	g.Go(func() error { return self.fetchStats(ctx) })
 
-	self.resp.UpdateStatusOnError(g.Wait(), monitoringplugin.CRITICAL, "", true)
-	return self.resp.GetStatusCode() == monitoringplugin.OK
+	return checkFetched(parentCtx, self.resp, g.Wait())
 }
 
 func (self *LastSeenCheck) fetchSystemStatus(ctx context.Context) error {
 	system, err := self.client.SystemStatus(ctx)
 	if err != nil {
-		return err
+		return newFetchError("system status", err)
 	}
 	self.system = system
 	return nil
@@ -139,7 +142,7 @@ func (self *LastSeenCheck) fetchSystemStatus(ctx context.Context) error {
 func (self *LastSeenCheck) fetchDevices(ctx context.Context) error {
 	devices, err := self.client.Devices(ctx)
 	if err != nil {
-		return err
+		return newFetchError("devices", err)
 	}
 
 	self.devices = make(map[string]api.DeviceConfiguration, len(devices))
@@ -152,10 +155,11 @@ func (self *LastSeenCheck) fetchDevices(ctx context.Context) error {
 
 func (self *LastSeenCheck) fetchStats(ctx context.Context) error {
 	stats, err := self.client.DeviceStats(ctx)
-	if err == nil {
-		self.stats = stats
+	if err != nil {
+		return newFetchError("device stats", err)
 	}
-	return err
+	self.stats = stats
+	return nil
 }
 
 func (self *LastSeenCheck) oldest() (deviceId string, seen time.Time) {
@@ -196,7 +196,7 @@ func TestLastSeenCheck_Run(t *testing.T) {
 		{
 			name: "without endpoints",
 			assertOutput: func(t *testing.T, rawOutput string) {
-				assert.Contains(t, rawOutput, "CRITICAL: ")
+				assert.Contains(t, rawOutput, "UNKNOWN: /rest/")
 			},
 		},
 		{
@@ -205,7 +205,7 @@ func TestLastSeenCheck_Run(t *testing.T) {
 				"/rest/system/status": systemStatusJSON,
 			},
 			assertOutput: func(t *testing.T, rawOutput string) {
-				assert.Contains(t, rawOutput, "CRITICAL: ")
+				assert.Contains(t, rawOutput, "UNKNOWN: /rest/")
 			},
 		},
 		{
@@ -215,7 +215,7 @@ func TestLastSeenCheck_Run(t *testing.T) {
 				"/rest/config/devices": devicesJSON,
 			},
 			assertOutput: func(t *testing.T, rawOutput string) {
-				assert.Contains(t, rawOutput, "CRITICAL: ")
+				assert.Contains(t, rawOutput, "UNKNOWN: /rest/")
 			},
 		},
 		{
@@ -360,7 +360,7 @@ func TestLastSeenCheck_Run(t *testing.T) {
 			if tt.with != nil {
 				tt.with(t, check)
 			}
-			require.Same(t, check, check.Run())
+			require.Same(t, check, check.Run(t.Context()))
 			resp := check.Response()
 			require.NotNil(t, resp)
 			t.Log(resp.GetInfo().RawOutput)
@@ -368,3 +368,17 @@ func TestLastSeenCheck_Run(t *testing.T) {
 		})
 	}
 }
+
+func TestLastSeenCheck_Run_timeout(t *testing.T) {
+	check := testNewLastSeenCheck(t, map[string]any{
+		"/rest/system/status":  api.SystemStatus{},
+		"/rest/config/devices": []api.DeviceConfiguration{},
+		"/rest/stats/device":   blockingAPI,
+	})
+
+	ctx := withTestTimeout(t, 50*time.Millisecond)
+	require.Same(t, check, check.Run(ctx))
+	assert.Equal(t,
+		"UNKNOWN: timed out after 50ms while fetching device stats",
+		check.Response().GetInfo().RawOutput)
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+
+	"github.com/dsh2dsh/go-monitoringplugin/v2"
+)
+
+// serviceEntry is a discovery or listener service from syncthing system status.
+type serviceEntry struct {
+	name string
+	kind string
+	err  string
+}
+
+func newServiceEntries[T any](entries map[string]T, kind func(string) string,
+	err func(T) string,
+) []serviceEntry {
+	services := make([]serviceEntry, 0, len(entries))
+	for name, entry := range entries {
+		services = append(services, serviceEntry{
+			name: name,
+			kind: kind(name),
+			err:  err(entry),
+		})
+	}
+	slices.SortFunc(services, func(a, b serviceEntry) int {
+		return cmp.Compare(a.name, b.name)
+	})
+	return services
+}
+
+// checkServices outputs errors of services with status of their kind,
+// configured by statuses. Errors of kinds missing in statuses are warnings and
+// OK status ignores errors. It returns number of working services, without
+// errors.
+func checkServices(resp *monitoringplugin.Response, services []serviceEntry,
+	statuses map[string]int,
+) int {
+	var working int
+	for _, s := range services {
+		if s.err == "" {
+			working++
+			continue
+		}
+
+		status, ok := statuses[s.kind]
+		if !ok {
+			status = monitoringplugin.WARNING
+		}
+		if status != monitoringplugin.OK {
+			resp.UpdateStatus(status, s.name+": "+s.err)
+		}
+	}
+
+	point := monitoringplugin.NewPerformanceDataPoint("working", working).
+		SetMin(0).SetMax(len(services))
+	if err := resp.AddPerformanceDataPoint(point); err != nil {
+		resp.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "", true)
+	}
+	return working
+}
+
+func servicesOkMsg(working, total int, what string) string {
+	return strconv.Itoa(working) + "/" + strconv.Itoa(total) + " " + what +
+		" working"
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var sizeUnits = [...]string{"K", "M", "G", "T"}
+
+// newSizeValue returns flag value, which sets p to size in bytes, given as
+// number of bytes or with K, M, G, T binary suffix, like 512M.
+func newSizeValue(val uint64, p *uint64) *sizeValue {
+	*p = val
+	return (*sizeValue)(p)
+}
+
+type sizeValue uint64
+
+func (self *sizeValue) String() string { return formatSize(uint64(*self)) }
+
+func (self *sizeValue) Set(s string) error {
+	size, err := parseSize(s)
+	if err != nil {
+		return err
+	}
+	*self = sizeValue(size)
+	return nil
+}
+
+func (self *sizeValue) Type() string { return "size" }
+
+func parseSize(s string) (uint64, error) {
+	num, mult := strings.TrimSuffix(strings.ToUpper(s), "B"), uint64(1)
+	for i, unit := range sizeUnits {
+		if n, ok := strings.CutSuffix(num, unit); ok {
+			num, mult = n, 1<<(10*(i+1))
+			break
+		}
+	}
+
+	size, err := strconv.ParseUint(num, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse size %q: %w", s, err)
+	} else if size > math.MaxUint64/mult {
+		return 0, fmt.Errorf("size %q out of range", s)
+	}
+	return size * mult, nil
+}
+
+// formatSize returns size in biggest unit, which keeps it integer, or rounded
+// to one decimal place in biggest unit, which keeps it >= 1.
+func formatSize(size uint64) string {
+	if size < 1<<10 {
+		return strconv.FormatUint(size, 10)
+	}
+
+	unit := -1
+	for i := range sizeUnits {
+		if size < 1<<(10*(i+1)) {
+			break
+		}
+		unit = i
+	}
+
+	div := uint64(1) << (10 * (unit + 1))
+	if size%div == 0 {
+		return strconv.FormatUint(size/div, 10) + sizeUnits[unit]
+	}
+	return strconv.FormatFloat(float64(size)/float64(div), 'f', 1, 64) +
+		sizeUnits[unit]
+}
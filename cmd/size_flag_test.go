@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSizeValue(t *testing.T) {
+	var size uint64
+	value := newSizeValue(1<<30, &size)
+	assert.Equal(t, uint64(1<<30), size)
+	assert.Equal(t, "1G", value.String())
+	assert.Equal(t, "size", value.Type())
+
+	require.NoError(t, value.Set("512M"))
+	assert.Equal(t, uint64(512<<20), size)
+
+	require.Error(t, value.Set("foo"))
+	assert.Equal(t, uint64(512<<20), size)
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected uint64
+		wantErr  bool
+	}{
+		{s: "0", expected: 0},
+		{s: "1024", expected: 1024},
+		{s: "10K", expected: 10 << 10},
+		{s: "512m", expected: 512 << 20},
+		{s: "2GB", expected: 2 << 30},
+		{s: "512mb", expected: 512 << 20},
+		{s: "1kb", expected: 1 << 10},
+		{s: "1Gb", expected: 1 << 30},
+		{s: "100b", expected: 100},
+		{s: "1T", expected: 1 << 40},
+		{s: "1.5G", wantErr: true},
+		{s: "-1", wantErr: true},
+		{s: "16777216T", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			size, err := parseSize(tt.s)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, size)
+		})
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		size     uint64
+		expected string
+	}{
+		{0, "0"},
+		{1023, "1023"},
+		{1024, "1K"},
+		{1536, "1.5K"},
+		{512 << 20, "512M"},
+		{35216400, "33.6M"},
+		{3 << 40, "3T"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, formatSize(tt.size))
+		})
+	}
+}
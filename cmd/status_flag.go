@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dsh2dsh/go-monitoringplugin/v2"
+)
+
+// newStatusValue returns flag value, which sets p to status code of plugin,
+// given as ok, warning or critical. OK status disables the check usually.
+func newStatusValue(val int, p *int) *statusValue {
+	*p = val
+	return (*statusValue)(p)
+}
+
+type statusValue int
+
+func (self *statusValue) String() string {
+	return strings.ToLower(monitoringplugin.StatusCode2Text(int(*self)))
+}
+
+func (self *statusValue) Set(s string) error {
+	switch strings.ToLower(s) {
+	case "ok":
+		*self = monitoringplugin.OK
+	case "warning":
+		*self = monitoringplugin.WARNING
+	case "critical":
+		*self = monitoringplugin.CRITICAL
+	default:
+		return fmt.Errorf("%q not one of ok, warning or critical", s)
+	}
+	return nil
+}
+
+func (self *statusValue) Type() string { return "status" }
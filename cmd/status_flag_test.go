@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/dsh2dsh/go-monitoringplugin/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusValue(t *testing.T) {
+	var status int
+	value := newStatusValue(monitoringplugin.WARNING, &status)
+	assert.Equal(t, monitoringplugin.WARNING, status)
+	assert.Equal(t, "warning", value.String())
+	assert.Equal(t, "status", value.Type())
+
+	tests := []struct {
+		s        string
+		expected int
+	}{
+		{"ok", monitoringplugin.OK},
+		{"critical", monitoringplugin.CRITICAL},
+		{"WARNING", monitoringplugin.WARNING},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			require.NoError(t, value.Set(tt.s))
+			assert.Equal(t, tt.expected, status)
+		})
+	}
+
+	require.Error(t, value.Set("unknown"))
+	assert.Equal(t, monitoringplugin.WARNING, status)
+}
@@ -0,0 +1,348 @@
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dsh2dsh/go-monitoringplugin/v2"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/dsh2dsh/check_syncthing/client"
+	"github.com/dsh2dsh/check_syncthing/client/api"
+)
+
+const versionOkMsg = "syncthing "
+
+// Kinds of upgrades, which --upgrade warns about.
+const (
+	upgradeAny   = "any"
+	upgradeMinor = "minor"
+	upgradeMajor = "major"
+)
+
+var (
+	minVersion     string
+	upgradeKind    string
+	remoteDistance int
+)
+
+var versionCmd = cobra.Command{
+	Use:   "version",
+	Short: "Check version of syncthing server",
+	Long: `Check version of syncthing server.
+
+It outputs warning status, if running version is older than --min-version.
+
+With --upgrade it checks for available upgrade and outputs warning status, if
+there is any upgrade, or minor or major upgrade only. If syncthing isn't
+allowed to check upgrades, like syncthing from a package, or it failed checking
+them, it outputs "upgrade check unavailable" with OK status.
+
+With --remote-distance it outputs warning status for connected devices, which
+run syncthing version with more than given number of minor releases between it
+and local version. Devices with another major version are always too far. Zero
+distance warns about any other minor version.`,
+
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if _, err := parseVersion(minVersion); minVersion != "" && err != nil {
+			return fmt.Errorf("--min-version: %w", err)
+		}
+
+		switch upgradeKind {
+		case "", upgradeAny, upgradeMinor, upgradeMajor:
+		default:
+			return fmt.Errorf("--upgrade %q not one of any, minor or major",
+				upgradeKind)
+		}
+		return nil
+	},
+
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := checkContext(cmd)
+		defer cancel()
+		NewVersionCheck(mustAPIClient()).
+			WithExcludeDevices(excludeDevices).
+			WithMinVersion(minVersion).
+			WithUpgrade(upgradeKind).
+			WithRemoteDistance(remoteDistance).
+			Run(ctx).Response().OutputAndExit()
+	},
+}
+
+func init() {
+	versionCmd.Flags().StringVar(&minVersion, "min-version", "",
+		"warning if running version is older, like v1.27.0")
+	versionCmd.Flags().StringVar(&upgradeKind, "upgrade", "",
+		"warning if upgrade is available: any, minor or major")
+	versionCmd.Flags().IntVar(&remoteDistance, "remote-distance", -1,
+		"warning if remote devices are more minor releases away (-1 disables)")
+}
+
+func NewVersionCheck(apiClient *client.Client) *VersionCheck {
+	c := &VersionCheck{client: apiClient, remoteDistance: -1}
+	return c.applyOptions()
+}
+
+type VersionCheck struct {
+	client *client.Client
+	resp   *monitoringplugin.Response
+
+	excludeDevices lookupDeviceId
+	minVersion     string
+	upgradeKind    string
+	remoteDistance int
+
+	version            *api.SystemVersion
+	upgrade            *api.SystemUpgrade
+	upgradeUnavailable bool
+	devices            map[string]api.DeviceConfiguration
+	conns              *api.Connections
+}
+
+func (self *VersionCheck) applyOptions() *VersionCheck {
+	if self.resp == nil {
+		self.resp = monitoringplugin.NewResponse(versionOkMsg)
+	}
+	return self
+}
+
+func (self *VersionCheck) WithExcludeDevices(devices []string) *VersionCheck {
+	self.excludeDevices = newLookupDeviceId(devices)
+	return self
+}
+
+// WithMinVersion configures minimal version of syncthing, like v1.27.0. Empty
+// version disables the check.
+func (self *VersionCheck) WithMinVersion(version string) *VersionCheck {
+	self.minVersion = version
+	return self
+}
+
+// WithUpgrade configures kind of upgrades to warn about: any, minor or major.
+// Empty kind disables the check.
+func (self *VersionCheck) WithUpgrade(kind string) *VersionCheck {
+	self.upgradeKind = kind
+	return self
+}
+
+// WithRemoteDistance configures how many minor releases connected devices can
+// be away from local version. Negative distance disables the check.
+func (self *VersionCheck) WithRemoteDistance(distance int) *VersionCheck {
+	self.remoteDistance = distance
+	return self
+}
+
+func (self *VersionCheck) Response() *monitoringplugin.Response {
+	return self.resp
+}
+
+func (self *VersionCheck) Run(ctx context.Context) *VersionCheck {
+	defer outputRetries(self.resp, self.client)
+
+	if !self.fetch(ctx) {
+		return self
+	}
+	self.resp.WithDefaultOkMessage(versionOkMsg + self.version.Version)
+
+	running, err := parseVersion(self.version.Version)
+	if self.resp.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "", true) {
+		return self
+	}
+
+	self.checkMinVersion(running)
+	if self.upgrade != nil {
+		self.checkUpgrade(running)
+	} else if self.upgradeUnavailable {
+		self.resp.UpdateStatus(monitoringplugin.OK, "upgrade check unavailable")
+	}
+	if self.conns != nil {
+		self.checkRemotes(running)
+	}
+	self.outputExcluded()
+	return self
+}
+
+func (self *VersionCheck) fetch(parentCtx context.Context) bool {
+	g, ctx := errgroup.WithContext(parentCtx)
+	g.SetLimit(fetchProcs)
+
+	g.Go(func() error { return self.fetchVersion(ctx) })
+	if self.upgradeKind != "" {
+		g.Go(func() error { return self.fetchUpgrade(ctx) })
+	}
+	if self.remoteDistance >= 0 {
+		g.Go(func() error { return self.fetchDevices(ctx) })
+		g.Go(func() error { return self.fetchConns(ctx) })
+	}
+
+	return checkFetchError(parentCtx, self.resp, g.Wait()) &&
+		self.version != nil
+}
+
+func (self *VersionCheck) fetchVersion(ctx context.Context) error {
+	version, err := self.client.SystemVersion(ctx)
+	if err != nil {
+		return newFetchError("system version", err)
+	}
+	self.version = version
+	return nil
+}
+
+// fetchUpgrade fetches available upgrade. Syncthing responds by server error,
+// if upgrades are disabled or unsupported, or it failed checking them, which
+// isn't an error of the check.
+func (self *VersionCheck) fetchUpgrade(ctx context.Context) error {
+	upgrade, err := self.client.SystemUpgrade(ctx)
+	var apiErr *client.APIError
+	if errors.As(err, &apiErr) && apiErr.ServerError() {
+		self.upgradeUnavailable = true
+		return nil
+	} else if err != nil {
+		return newFetchError("system upgrade", err)
+	}
+	self.upgrade = upgrade
+	return nil
+}
+
+func (self *VersionCheck) fetchDevices(ctx context.Context) error {
+	devices, err := self.client.Devices(ctx)
+	if err != nil {
+		return newFetchError("devices", err)
+	}
+
+	self.devices = make(map[string]api.DeviceConfiguration, len(devices))
+	for i := range devices {
+		d := &devices[i]
+		self.devices[d.DeviceID] = devices[i]
+	}
+	return nil
+}
+
+func (self *VersionCheck) fetchConns(ctx context.Context) error {
+	conns, err := self.client.Connections(ctx)
+	if err != nil {
+		return newFetchError("connections", err)
+	}
+	self.conns = conns
+	return nil
+}
+
+func (self *VersionCheck) checkMinVersion(running semVersion) {
+	if self.minVersion == "" {
+		return
+	}
+
+	minVersion, err := parseVersion(self.minVersion)
+	if self.resp.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "", true) {
+		return
+	} else if running.Compare(minVersion) < 0 {
+		self.resp.UpdateStatus(monitoringplugin.WARNING, self.version.Version+
+			" older than "+self.minVersion)
+	}
+}
+
+func (self *VersionCheck) checkUpgrade(running semVersion) {
+	if !self.upgrade.Newer {
+		return
+	}
+
+	latest, err := parseVersion(self.upgrade.Latest)
+	if self.resp.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "", true) {
+		return
+	}
+
+	var available bool
+	switch self.upgradeKind {
+	case upgradeAny:
+		available = true
+	case upgradeMinor:
+		available = latest.major > running.major || latest.minor > running.minor
+	case upgradeMajor:
+		available = latest.major > running.major
+	}
+
+	if available {
+		self.resp.UpdateStatus(monitoringplugin.WARNING,
+			"upgrade available: "+self.upgrade.Latest)
+	}
+}
+
+func (self *VersionCheck) checkRemotes(running semVersion) {
+	for _, id := range slices.Sorted(maps.Keys(self.conns.Connections)) {
+		conn := self.conns.Connections[id]
+		if !conn.Connected || self.excludeDevices.Has(id) {
+			continue
+		}
+
+		remote, err := parseVersion(conn.ClientVersion)
+		if err != nil {
+			// Not syncthing or something unusual. Nothing to compare with.
+			continue
+		} else if running.Distance(remote) > self.remoteDistance {
+			self.resp.UpdateStatus(monitoringplugin.WARNING,
+				"device "+deviceName(id, self.devices[id].Name)+" runs "+
+					conn.ClientVersion)
+		}
+	}
+}
+
+func (self *VersionCheck) outputExcluded() {
+	if self.excludeDevices.Excluded() {
+		self.resp.UpdateStatus(self.resp.GetStatusCode(),
+			"excluded: "+self.excludeDevices.ExcludedString(self.devices))
+	}
+}
+
+// --------------------------------------------------
+
+func parseVersion(s string) (semVersion, error) {
+	var v semVersion
+	version, _, _ := strings.Cut(strings.TrimPrefix(s, "v"), "-")
+	version, _, _ = strings.Cut(version, "+")
+
+	parts := strings.Split(version, ".")
+	if len(parts) != 3 {
+		return v, fmt.Errorf("parse version %q: expected major.minor.patch", s)
+	}
+
+	for i, p := range [...]*int{&v.major, &v.minor, &v.patch} {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil {
+			return v, fmt.Errorf("parse version %q: %w", s, err)
+		} else if n < 0 {
+			return v, fmt.Errorf("parse version %q: negative number", s)
+		}
+		*p = n
+	}
+	return v, nil
+}
+
+// semVersion is a syncthing version, like v1.27.4, without pre-release and
+// build parts.
+type semVersion struct {
+	major, minor, patch int
+}
+
+func (self semVersion) Compare(other semVersion) int {
+	return cmp.Or(
+		cmp.Compare(self.major, other.major),
+		cmp.Compare(self.minor, other.minor),
+		cmp.Compare(self.patch, other.patch))
+}
+
+// Distance returns number of minor releases between self and other, or
+// [math.MaxInt] if they are different major versions.
+func (self semVersion) Distance(other semVersion) int {
+	if self.major != other.major {
+		return math.MaxInt
+	}
+	return max(self.minor, other.minor) - min(self.minor, other.minor)
+}
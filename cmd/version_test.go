@@ -0,0 +1,273 @@
+package cmd
+
+import (
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/dsh2dsh/go-monitoringplugin/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dsh2dsh/check_syncthing/client/api"
+)
+
+func TestNewVersionCheck(t *testing.T) {
+	check := testNewVersionCheck(t, nil)
+	resp := check.Response()
+	require.NotNil(t, resp)
+	assert.Equal(t, "OK: "+versionOkMsg, resp.GetInfo().RawOutput)
+}
+
+func testNewVersionCheck(t *testing.T, endpoints map[string]any,
+) *VersionCheck {
+	check := NewVersionCheck(newTestClient(t, fakeAPI(t, endpoints)))
+	require.NotNil(t, check)
+	return check
+}
+
+func TestVersionCheck_applyOptionsWithResp(t *testing.T) {
+	resp := monitoringplugin.NewResponse("def ok msg")
+	check := &VersionCheck{resp: resp}
+	require.Same(t, check, check.applyOptions())
+	assert.Same(t, resp, check.Response())
+}
+
+func TestVersionCheck_Run(t *testing.T) {
+	const testId2 = "XXXXXX2-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX"
+	const testId3 = "XXXXXX3-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX"
+	const testId4 = "XXXXXX4-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX-XXXXXXX"
+
+	version := api.SystemVersion{Version: "v1.27.4"}
+	upgrade := func(latest string, majorNewer bool) api.SystemUpgrade {
+		return api.SystemUpgrade{
+			Latest:     latest,
+			MajorNewer: majorNewer,
+			Newer:      true,
+			Running:    version.Version,
+		}
+	}
+
+	devices := []api.DeviceConfiguration{
+		{DeviceID: testId2, Name: "nas"},
+		{DeviceID: testId3, Name: "pc3"},
+		{DeviceID: testId4, Name: "pc4"},
+	}
+	conns := api.Connections{
+		Connections: map[string]api.ConnectionStats{
+			testId2: {Connected: true, ClientVersion: "v1.25.0"},
+			testId3: {Connected: true, ClientVersion: "v2.0.0-rc.1"},
+			testId4: {ClientVersion: "v1.20.0"},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		with      func(t *testing.T, check *VersionCheck)
+		endpoints map[string]any
+		expected  string
+	}{
+		{
+			name: "OK",
+			with: func(t *testing.T, check *VersionCheck) {
+				check.WithMinVersion("v1.27.0")
+			},
+			endpoints: map[string]any{"/rest/system/version": version},
+			expected:  "OK: " + versionOkMsg + "v1.27.4",
+		},
+		{
+			name: "min version",
+			with: func(t *testing.T, check *VersionCheck) {
+				check.WithMinVersion("v1.27.10")
+			},
+			endpoints: map[string]any{"/rest/system/version": version},
+			expected:  "WARNING: v1.27.4 older than v1.27.10",
+		},
+		{
+			name: "upgrade any",
+			with: func(t *testing.T, check *VersionCheck) {
+				check.WithUpgrade(upgradeAny)
+			},
+			endpoints: map[string]any{
+				"/rest/system/version": version,
+				"/rest/system/upgrade": upgrade("v1.27.5", false),
+			},
+			expected: "WARNING: upgrade available: v1.27.5",
+		},
+		{
+			name: "upgrade minor skips patch",
+			with: func(t *testing.T, check *VersionCheck) {
+				check.WithUpgrade(upgradeMinor)
+			},
+			endpoints: map[string]any{
+				"/rest/system/version": version,
+				"/rest/system/upgrade": upgrade("v1.27.5", false),
+			},
+			expected: "OK: " + versionOkMsg + "v1.27.4",
+		},
+		{
+			name: "upgrade minor",
+			with: func(t *testing.T, check *VersionCheck) {
+				check.WithUpgrade(upgradeMinor)
+			},
+			endpoints: map[string]any{
+				"/rest/system/version": version,
+				"/rest/system/upgrade": upgrade("v1.28.0", false),
+			},
+			expected: "WARNING: upgrade available: v1.28.0",
+		},
+		{
+			name: "upgrade major skips minor",
+			with: func(t *testing.T, check *VersionCheck) {
+				check.WithUpgrade(upgradeMajor)
+			},
+			endpoints: map[string]any{
+				"/rest/system/version": version,
+				"/rest/system/upgrade": upgrade("v1.28.0", false),
+			},
+			expected: "OK: " + versionOkMsg + "v1.27.4",
+		},
+		{
+			name: "upgrade major",
+			with: func(t *testing.T, check *VersionCheck) {
+				check.WithUpgrade(upgradeMajor)
+			},
+			endpoints: map[string]any{
+				"/rest/system/version": version,
+				"/rest/system/upgrade": upgrade("v2.0.1", true),
+			},
+			expected: "WARNING: upgrade available: v2.0.1",
+		},
+		{
+			name: "upgrade unsupported",
+			with: func(t *testing.T, check *VersionCheck) {
+				check.WithUpgrade(upgradeAny)
+			},
+			endpoints: map[string]any{
+				"/rest/system/version": version,
+				"/rest/system/upgrade": statusAPI(http.StatusServiceUnavailable),
+			},
+			expected: "OK: syncthing v1.27.4\nupgrade check unavailable",
+		},
+		{
+			name: "upgrade check failed",
+			with: func(t *testing.T, check *VersionCheck) {
+				check.WithUpgrade(upgradeAny).WithMinVersion("v1.28.0")
+			},
+			endpoints: map[string]any{
+				"/rest/system/version": version,
+				"/rest/system/upgrade": statusAPI(http.StatusInternalServerError),
+			},
+			expected: "WARNING: v1.27.4 older than v1.28.0\n" +
+				"upgrade check unavailable",
+		},
+		{
+			name: "upgrade access denied",
+			with: func(t *testing.T, check *VersionCheck) {
+				check.WithUpgrade(upgradeAny)
+			},
+			endpoints: map[string]any{
+				"/rest/system/version": version,
+				"/rest/system/upgrade": statusAPI(http.StatusForbidden),
+			},
+			expected: "UNKNOWN: access denied, check API key " +
+				"(error: system upgrade: unexpected syncthing response: " +
+				"403 Forbidden ())",
+		},
+		{
+			name: "remote distance",
+			with: func(t *testing.T, check *VersionCheck) {
+				check.WithRemoteDistance(1)
+			},
+			endpoints: map[string]any{
+				"/rest/system/version":     version,
+				"/rest/config/devices":     devices,
+				"/rest/system/connections": conns,
+			},
+			expected: `WARNING: device XXXXXX2 (nas) runs v1.25.0
+device XXXXXX3 (pc3) runs v2.0.0-rc.1`,
+		},
+		{
+			name: "remote distance excluded",
+			with: func(t *testing.T, check *VersionCheck) {
+				check.WithRemoteDistance(2).
+					WithExcludeDevices([]string{"XXXXXX3"})
+			},
+			endpoints: map[string]any{
+				"/rest/system/version":     version,
+				"/rest/config/devices":     devices,
+				"/rest/system/connections": conns,
+			},
+			expected: `OK: syncthing v1.27.4
+excluded: XXXXXX3 (pc3)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := testNewVersionCheck(t, tt.endpoints)
+			if tt.with != nil {
+				tt.with(t, check)
+			}
+			require.Same(t, check, check.Run(t.Context()))
+			rawOutput := check.Response().GetInfo().RawOutput
+			t.Log(rawOutput)
+			assert.Equal(t, tt.expected, rawOutput)
+		})
+	}
+}
+
+func TestVersionCheck_Run_timeout(t *testing.T) {
+	check := testNewVersionCheck(t, map[string]any{
+		"/rest/system/version": blockingAPI,
+	})
+
+	ctx := withTestTimeout(t, 50*time.Millisecond)
+	require.Same(t, check, check.Run(ctx))
+	assert.Equal(t,
+		"UNKNOWN: timed out after 50ms while fetching system version",
+		check.Response().GetInfo().RawOutput)
+}
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected semVersion
+		wantErr  bool
+	}{
+		{s: "v1.27.4", expected: semVersion{1, 27, 4}},
+		{s: "1.27.4", expected: semVersion{1, 27, 4}},
+		{s: "v2.0.0-rc.1", expected: semVersion{2, 0, 0}},
+		{s: "v1.27.4+3-g1234567", expected: semVersion{1, 27, 4}},
+		{s: "", wantErr: true},
+		{s: "v1.27", wantErr: true},
+		{s: "v1.x.4", wantErr: true},
+		{s: "v1.-1.4", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			v, err := parseVersion(tt.s)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, v)
+		})
+	}
+}
+
+func TestSemVersion(t *testing.T) {
+	v := semVersion{1, 27, 4}
+	assert.Equal(t, 0, v.Compare(semVersion{1, 27, 4}))
+	assert.Equal(t, -1, v.Compare(semVersion{1, 27, 10}))
+	assert.Equal(t, 1, v.Compare(semVersion{1, 9, 10}))
+	assert.Equal(t, -1, v.Compare(semVersion{2, 0, 0}))
+
+	assert.Equal(t, 0, v.Distance(semVersion{1, 27, 0}))
+	assert.Equal(t, 2, v.Distance(semVersion{1, 25, 0}))
+	assert.Equal(t, 3, v.Distance(semVersion{1, 30, 0}))
+	assert.Equal(t, math.MaxInt, v.Distance(semVersion{2, 27, 4}))
+}